@@ -0,0 +1,131 @@
+package pquads
+
+import (
+	"sync"
+
+	"github.com/cayleygraph/quad"
+)
+
+// Allocator constructs quad values decoded by a Reader, for example to pool, intern or arena-allocate them.
+// Quads themselves are returned by value and are never allocated by the reader.
+//
+// Both methods receive a decoded message and must return an equivalent value; they may call ToNative
+// on the message for value types they don't handle. The message is not used by the reader after the call.
+//
+// Values returned by the allocator may be returned by the reader again after the quad they were created for:
+//   - as a carried-over subject, predicate or object of compacted quads, until the reader decodes a different value
+//     in the same direction or reaches a checkpoint;
+//   - as a dictionary entry in files with Options.Dictionary, until the next checkpoint;
+//   - as the subject of all quads in a subject group.
+//
+// Thus, an allocator that frees values in bulk must keep them valid for as long as the reader may return them,
+// for example by freeing values at checkpoints (see Reader.LastCheckpoint). Files written with Options.Full
+// and without a dictionary never reuse values across quads.
+type Allocator interface {
+	// Value converts a decoded value to quad.Value.
+	Value(v *Value) quad.Value
+	// Ref converts a decoded IRI or blank node of a file written with Options.Strict.
+	Ref(v *StrictQuad_Ref) quad.Value
+}
+
+// SetAllocator sets an allocator for quad values decoded by the reader. Nil restores the default allocation.
+func (r *Reader) SetAllocator(a Allocator) {
+	r.alloc = a
+}
+
+// ValuePool interns quad values, so equal values decoded by different readers share the same memory.
+// See Reader.SetValuePool.
+type ValuePool interface {
+	// Intern returns a value equal to v, possibly one that was passed to Intern before.
+	Intern(v quad.Value) quad.Value
+}
+
+// SetValuePool sets a pool to intern all values decoded by the reader. Nil disables interning.
+//
+// The reader calls the pool from the goroutine that reads quads, thus a pool shared by readers used
+// from different goroutines must be safe for concurrent use, as the one returned by NewValuePool is.
+// If the reader also has an Allocator, values constructed by it are passed to the pool, which may keep them
+// for longer than the allocator expects.
+func (r *Reader) SetValuePool(p ValuePool) {
+	r.pool = p
+}
+
+// NewValuePool creates a value pool that is safe for concurrent use by multiple readers.
+//
+// It interns IRIs, blank nodes, strings and typed and language-tagged strings, and returns all other values as-is.
+// Interned values are kept until the pool is garbage-collected.
+func NewValuePool() ValuePool {
+	return &valuePool{m: make(map[quad.Value]quad.Value)}
+}
+
+type valuePool struct {
+	mu sync.RWMutex
+	m  map[quad.Value]quad.Value
+}
+
+func (p *valuePool) Intern(v quad.Value) quad.Value {
+	switch v.(type) {
+	case quad.IRI, quad.BNode, quad.String, quad.TypedString, quad.LangString:
+	default:
+		return v
+	}
+	p.mu.RLock()
+	iv, ok := p.m[v]
+	p.mu.RUnlock()
+	if ok {
+		return iv
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if iv, ok = p.m[v]; ok {
+		return iv
+	}
+	p.m[v] = v
+	return v
+}
+
+func (r *Reader) value(v *Value) quad.Value {
+	if v == nil {
+		return nil
+	}
+	var qv quad.Value
+	if r.alloc != nil {
+		qv = r.alloc.Value(v)
+	} else {
+		qv = v.ToNative()
+	}
+	if r.pool != nil {
+		qv = r.pool.Intern(qv)
+	}
+	return qv
+}
+
+func (r *Reader) ref(v *StrictQuad_Ref) quad.Value {
+	if v == nil {
+		return nil
+	}
+	var qv quad.Value
+	if r.alloc != nil {
+		qv = r.alloc.Ref(v)
+	} else {
+		qv = v.ToNative()
+	}
+	if r.pool != nil {
+		qv = r.pool.Intern(qv)
+	}
+	return qv
+}
+
+func (r *Reader) wireQuad(m *WireQuad) quad.Quad {
+	if r.alloc == nil && r.pool == nil {
+		return m.ToNative()
+	}
+	return quad.Quad{Subject: r.value(m.Subject), Predicate: r.value(m.Predicate), Object: r.value(m.Object), Label: r.value(m.Label)}
+}
+
+func (r *Reader) strictQuad(m *StrictQuad) quad.Quad {
+	if r.alloc == nil && r.pool == nil {
+		return m.ToNative()
+	}
+	return quad.Quad{Subject: r.ref(m.Subject), Predicate: r.ref(m.Predicate), Object: r.value(m.Object), Label: r.ref(m.Label)}
+}
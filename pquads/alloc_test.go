@@ -0,0 +1,109 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+// internAllocator returns the same value for repeated IRIs.
+type internAllocator struct {
+	iris  map[string]quad.Value
+	calls int
+}
+
+func (a *internAllocator) iri(s string) quad.Value {
+	a.calls++
+	v, ok := a.iris[s]
+	if !ok {
+		v = quad.IRI(s)
+		a.iris[s] = v
+	}
+	return v
+}
+
+func (a *internAllocator) Value(v *pquads.Value) quad.Value {
+	if iri, ok := v.Value.(*pquads.Value_Iri); ok {
+		return a.iri(iri.Iri)
+	}
+	a.calls++
+	return v.ToNative()
+}
+
+func (a *internAllocator) Ref(v *pquads.StrictQuad_Ref) quad.Value {
+	if iri, ok := v.Value.(*pquads.StrictQuad_Ref_Iri); ok {
+		return a.iri(iri.Iri)
+	}
+	a.calls++
+	return v.ToNative()
+}
+
+func TestAllocator(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	for _, opts := range []pquads.Options{
+		{},
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{Dictionary: true, Strict: true},
+	} {
+		data := writeQuads(t, &opts, in)
+		a := &internAllocator{iris: make(map[string]quad.Value)}
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.SetAllocator(a)
+		out, err := quad.ReadAll(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", opts)
+		} else if a.calls == 0 {
+			t.Fatalf("%+v: allocator was not used", opts)
+		} else if len(a.iris) != 13 {
+			t.Fatalf("%+v: unexpected number of distinct IRIs: %d", opts, len(a.iris))
+		}
+	}
+}
+
+func TestValuePool(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	files := [][]byte{writeQuads(t, nil, in), writeQuads(t, &pquads.Options{Strict: true}, in)}
+	pool := pquads.NewValuePool()
+	out := make([][]quad.Quad, len(files))
+	errs := make([]error, len(files))
+	var wg sync.WaitGroup
+	for i, data := range files {
+		wg.Add(1)
+		go func(i int, data []byte) {
+			defer wg.Done()
+			r := pquads.NewReader(bytes.NewReader(data), 0)
+			r.SetValuePool(pool)
+			out[i], errs[i] = quad.ReadAll(ctx, r)
+		}(i, data)
+	}
+	wg.Wait()
+	for i := range files {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		} else if !reflect.DeepEqual(in, out[i]) {
+			t.Fatal("corrupted quads")
+		}
+	}
+	// equal values of both files share memory
+	ptr := func(v quad.Value) uintptr {
+		s := string(v.(quad.IRI))
+		return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	}
+	for i, q := range out[0] {
+		if ptr(q.Subject) != ptr(out[1][i].Subject) || ptr(q.Predicate) != ptr(out[1][i].Predicate) {
+			t.Fatalf("values of quad %d are not interned", i)
+		}
+	}
+}
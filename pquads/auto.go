@@ -0,0 +1,64 @@
+package pquads
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/cayleygraph/quad"
+)
+
+// autoPeek is the number of leading bytes inspected by AutoReader.
+const autoPeek = 512
+
+// AutoReader detects the format of a stream by its leading bytes and returns a reader for it.
+// Streams starting with pquads magic (optionally gzip-compressed) are read with NewReader,
+// see it for the description of maxSize. Streams that look like N-Quads text are read with
+// the registered "nquads" format, thus the nquads package must be imported for them to be supported.
+//
+// An empty stream is treated as an empty N-Quads document. An error is returned if the stream
+// matches neither of the formats. Gzip streams are always assumed to be pquads.
+func AutoReader(r io.Reader, maxSize int) (quad.ReadCloser, error) {
+	br := bufio.NewReaderSize(r, autoPeek)
+	pref, err := br.Peek(autoPeek)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if isPQuads(pref) {
+		qr := NewReader(br, maxSize)
+		if qr.err != nil {
+			return nil, qr.err
+		}
+		return qr, nil
+	} else if !isNQuads(pref) {
+		return nil, errors.New("unknown quad format: neither pquads nor nquads")
+	}
+	f := quad.FormatByName("nquads")
+	if f == nil || f.Reader == nil {
+		return nil, errors.New("nquads format is not registered")
+	}
+	return f.Reader(br), nil
+}
+
+// isPQuads checks if data starts with pquads magic or a gzip header.
+func isPQuads(data []byte) bool {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		return true
+	}
+	return len(data) >= len(magic) && string(data[:len(magic)]) == string(magic[:])
+}
+
+// isNQuads checks if data looks like the beginning of an N-Quads document:
+// the first non-space character must start a term or a comment.
+func isNQuads(data []byte) bool {
+	for _, c := range data {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '<', '_', '#':
+			return true
+		}
+		return false
+	}
+	return true
+}
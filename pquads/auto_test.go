@@ -0,0 +1,52 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	_ "github.com/cayleygraph/quad/nquads"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestAutoReader(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(20)
+	var nq bytes.Buffer
+	for _, q := range quads {
+		nq.WriteString(q.NQuad() + "\n")
+	}
+	for _, c := range []struct {
+		name string
+		data []byte
+		exp  []quad.Quad
+	}{
+		{name: "pquads", data: writeQuads(t, nil, quads), exp: quads},
+		{name: "gzip", data: writeQuads(t, &pquads.Options{Compress: true}, quads), exp: quads},
+		{name: "nquads", data: nq.Bytes(), exp: quads},
+		{name: "comment", data: []byte("\n# comment\n" + quads[0].NQuad() + "\n"), exp: quads[:1]},
+		{name: "empty", data: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r, err := pquads.AutoReader(bytes.NewReader(c.data), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			got, err := quad.ReadAll(ctx, r)
+			if err != nil {
+				t.Fatal(err)
+			} else if len(got) != 0 || len(c.exp) != 0 {
+				if !reflect.DeepEqual(got, c.exp) {
+					t.Fatalf("unexpected quads:\n%v\nvs\n%v", got, c.exp)
+				}
+			}
+		})
+	}
+	if _, err := pquads.AutoReader(strings.NewReader("{\"subject\": \"a\"}"), 0); err == nil {
+		t.Fatal("expected an error for unknown format")
+	}
+}
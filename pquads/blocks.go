@@ -0,0 +1,287 @@
+package pquads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads/pio"
+	"google.golang.org/protobuf/proto"
+)
+
+// footerSize is the size of the footer record, including the length prefix.
+const footerSize = 12
+
+// writeIndex writes the block index and the footer records.
+func (w *Writer) writeIndex() error {
+	if w.err != nil {
+		return w.err
+	}
+	idx := &BlockIndex{Blocks: w.blocks, Quads: uint64(w.n), FirstSubject: MakeValue(w.first), LastSubject: MakeValue(w.last)}
+	foot := &Footer{IndexOffset: uint64(w.off)}
+	var mi, mf proto.Message
+	if w.opts.Strict {
+		mi, mf = &StrictQuad{Index: idx}, &StrictQuad{Footer: foot}
+	} else {
+		mi, mf = &WireQuad{Index: idx}, &WireQuad{Footer: foot}
+	}
+	var n int
+	n, w.err = w.pw.WriteMsg(mi)
+	w.off += int64(n)
+	if w.err != nil {
+		return w.err
+	}
+	n, w.err = w.pw.WriteMsg(mf)
+	w.off += int64(n)
+	return w.err
+}
+
+// IndexedReader provides random access to blocks of a pquads file written with Options.BlockQuads.
+type IndexedReader struct {
+	ra      io.ReaderAt
+	maxSize int
+	opts    Options
+	idx     *BlockIndex
+	end     int64 // end of the last block
+	hsize   int
+	hsum    uint32 // see Reader.hsum
+}
+
+// NewIndexedReader opens a pquads file with a block index. Size is the size of the file.
+// See NewReader for the description of maxSize. Compressed files are not supported.
+func NewIndexedReader(ra io.ReaderAt, size int64, maxSize int) (*IndexedReader, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	hr, _ := openReader(io.NewSectionReader(ra, 0, size), maxSize)
+	if hr.err != nil {
+		return nil, hr.err
+	} else if hr.zr != nil {
+		return nil, errors.New("compressed pquads files cannot be indexed")
+	}
+	idx, off, err := readIndex(ra, size)
+	if err != nil {
+		return nil, err
+	}
+	return &IndexedReader{ra: ra, maxSize: maxSize, opts: hr.opts, idx: idx, end: off,
+		hsize: hr.hsize, hsum: hr.hsum}, nil
+}
+
+var errNoIndex = errors.New("no block index in pquads file")
+
+// readIndex reads the block index of a file from the footer, and returns it with its offset.
+// It returns errNoIndex if the file doesn't end with a footer.
+func readIndex(ra io.ReaderAt, size int64) (*BlockIndex, int64, error) {
+	if size < footerSize {
+		return nil, 0, errNoIndex
+	}
+	buf := make([]byte, footerSize)
+	if _, err := ra.ReadAt(buf, size-footerSize); err != nil {
+		return nil, 0, err
+	}
+	var foot WireQuad
+	if buf[0] != footerSize-1 || foot.UnmarshalVT(buf[1:]) != nil || foot.Footer == nil {
+		return nil, 0, errNoIndex
+	}
+	off := int64(foot.Footer.IndexOffset)
+	if off < 0 || off > size-footerSize {
+		return nil, 0, fmt.Errorf("invalid block index offset: %d", off)
+	}
+	// the index may be larger than a quad, thus it's only limited by the file size
+	var m WireQuad
+	pr := pio.NewReader(io.NewSectionReader(ra, off, size-footerSize-off), int(size))
+	if err := pr.ReadMsg(&m); err != nil {
+		return nil, 0, fmt.Errorf("cannot read block index: %w", err)
+	} else if m.Index == nil || len(m.Index.Blocks) == 0 {
+		return nil, 0, errors.New("invalid block index")
+	}
+	if err := checkIndex(m.Index, off); err != nil {
+		return nil, 0, err
+	}
+	return m.Index, off, nil
+}
+
+// NewIndexedReaderFrom opens a pquads file with a block index stored separately, for example one
+// created by BuildIndex. Size is the size of the file. See NewReader for the description of maxSize.
+func NewIndexedReaderFrom(ra io.ReaderAt, size int64, maxSize int, idx *BlockIndex) (*IndexedReader, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	hr, _ := openReader(io.NewSectionReader(ra, 0, size), maxSize)
+	if hr.err != nil {
+		return nil, hr.err
+	} else if hr.zr != nil {
+		return nil, errors.New("compressed pquads files cannot be indexed")
+	} else if idx == nil || len(idx.Blocks) == 0 {
+		return nil, errors.New("invalid block index")
+	} else if err := checkIndex(idx, size); err != nil {
+		return nil, err
+	}
+	return &IndexedReader{ra: ra, maxSize: maxSize, opts: hr.opts, idx: idx, end: size,
+		hsize: hr.hsize, hsum: hr.hsum}, nil
+}
+
+// checkIndex checks that offsets of blocks are sorted and don't exceed the end of the data.
+func checkIndex(idx *BlockIndex, end int64) error {
+	for i, b := range idx.Blocks {
+		if int64(b.Offset) > end || (i > 0 && b.Offset < idx.Blocks[i-1].Offset) {
+			return fmt.Errorf("invalid offset for block %d: %d", i, b.Offset)
+		}
+	}
+	return nil
+}
+
+// NumBlocks returns the number of blocks in the file.
+func (r *IndexedReader) NumBlocks() int {
+	return len(r.idx.Blocks)
+}
+
+// NumQuads returns the number of quads in the file.
+func (r *IndexedReader) NumQuads() int {
+	return int(r.idx.Quads)
+}
+
+// SubjectRange returns the subjects of the first and the last quad in the file, if they are stored in the index.
+// Only files written by MergeIndexed have them, thus all subjects of such files are within this range.
+func (r *IndexedReader) SubjectRange() (first, last quad.Value, ok bool) {
+	if r.idx.FirstSubject == nil || r.idx.LastSubject == nil {
+		return nil, nil, false
+	}
+	return r.idx.FirstSubject.ToNative(), r.idx.LastSubject.ToNative(), true
+}
+
+// BlockOrdinal returns the ordinal of the first quad in a given block.
+func (r *IndexedReader) BlockOrdinal(i int) int {
+	return int(r.idx.Blocks[i].Ordinal)
+}
+
+// Block returns a reader for quads of a given block.
+func (r *IndexedReader) Block(i int) *Reader {
+	start := int64(r.idx.Blocks[i].Offset)
+	end := r.end
+	if i+1 < len(r.idx.Blocks) {
+		end = int64(r.idx.Blocks[i+1].Offset)
+	}
+	qr := &Reader{
+		src: &countingReader{r: io.NewSectionReader(r.ra, start, end-start)}, opts: r.opts,
+		off: start, hsize: r.hsize, hsum: r.hsum, maxSize: r.maxSize,
+	}
+	qr.pr = pio.NewReader(qr.src, r.maxSize).(pio.RawReader)
+	qr.resetLangs()
+	qr.resetTime()
+	return qr
+}
+
+// Reverse returns a function that iterates over all quads of the file in reverse order.
+// It has the same type as iter.Seq2[quad.Quad, error]. Iteration stops after the first error.
+//
+// Blocks are read from the last to the first one, and each block is decoded into memory and yielded backward,
+// thus it keeps all quads of one block in memory at a time.
+func (r *IndexedReader) Reverse(ctx context.Context) func(yield func(quad.Quad, error) bool) {
+	return func(yield func(quad.Quad, error) bool) {
+		var buf []quad.Quad
+		for i := r.NumBlocks() - 1; i >= 0; i-- {
+			if err := ctx.Err(); err != nil {
+				yield(quad.Quad{}, err)
+				return
+			}
+			buf = buf[:0]
+			br := r.Block(i)
+			for {
+				q, err := br.ReadQuad(ctx)
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					yield(quad.Quad{}, fmt.Errorf("block %d: %w", i, err))
+					return
+				}
+				buf = append(buf, q)
+			}
+			for j := len(buf) - 1; j >= 0; j-- {
+				if !yield(buf[j], nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// BuildIndex scans a pquads file of a given size and builds a block index for it, which can be used with
+// NewIndexedReaderFrom. It's intended for files written without Options.BlockQuads.
+// See NewReader for the description of maxSize. Compressed files are not supported.
+//
+// A block can only start at a record that doesn't depend on the previous ones: a checkpoint, or a quad that
+// has all values, which is every quad for files written with the Full option. A new block is started at the first
+// such record after at least blockQuads quads, thus blocks of compacted files may be larger than blockQuads.
+// For files with a dictionary, blocks can only start at checkpoints, and a file without checkpoints is indexed
+// as a single block. Sources of quads (see Writer.SetSource) are not restored at the start of a block.
+func BuildIndex(ra io.ReaderAt, size int64, maxSize int, blockQuads int) (*BlockIndex, error) {
+	if blockQuads <= 0 {
+		return nil, fmt.Errorf("invalid number of quads per block: %d", blockQuads)
+	}
+	ctx := context.Background()
+	rr := NewRecordReader(io.NewSectionReader(ra, 0, size), maxSize)
+	defer rr.Close()
+	if rr.err != nil {
+		return nil, rr.err
+	} else if rr.r.zr != nil {
+		return nil, errors.New("compressed pquads files cannot be indexed")
+	}
+	opts := rr.r.opts
+	idx := &BlockIndex{}
+	for {
+		rec, err := rr.ReadRecord(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if rec.Type == RecordHeader {
+			// the first block starts right after the header
+			idx.Blocks = append(idx.Blocks, &BlockIndex_Block{Offset: uint64(rec.Offset + int64(rec.Size))})
+			continue
+		}
+		var quads uint64
+		switch rec.Type {
+		case RecordQuad:
+			quads = 1
+		case RecordGroup:
+			quads = uint64(countField(rec.Raw, groupField))
+		}
+		last := idx.Blocks[len(idx.Blocks)-1]
+		if idx.Quads-last.Ordinal >= uint64(blockQuads) && standaloneRecord(&rec, opts) {
+			idx.Blocks = append(idx.Blocks, &BlockIndex_Block{Offset: uint64(rec.Offset), Ordinal: idx.Quads})
+		}
+		idx.Quads += quads
+	}
+	return idx, nil
+}
+
+// standaloneRecord checks if a record can be decoded without the state from previous records.
+func standaloneRecord(rec *Record, opts Options) bool {
+	switch rec.Type {
+	case RecordCheckpoint:
+		return true
+	case RecordQuad, RecordGroup:
+	default:
+		return false
+	}
+	if opts.valueState() {
+		return false
+	}
+	var spo [3]bool // directions are present
+	if m := rec.Strict; m != nil {
+		spo = [3]bool{m.Subject != nil, m.Predicate != nil, m.Object != nil}
+		if len(m.Group) != 0 {
+			spo[1], spo[2] = m.Group[0].Predicate != nil, m.Group[0].Object != nil
+		}
+	} else if m := rec.Wire; m != nil {
+		spo = [3]bool{m.Subject != nil, m.Predicate != nil, m.Object != nil}
+		if len(m.Group) != 0 {
+			spo[1], spo[2] = m.Group[0].Predicate != nil, m.Group[0].Object != nil
+		}
+	}
+	return spo[0] && spo[1] && spo[2]
+}
@@ -0,0 +1,226 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestIndexedReader(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(105)
+	for _, opts := range []pquads.Options{
+		{BlockQuads: 10},
+		{BlockQuads: 10, Strict: true},
+		{BlockQuads: 10, Full: true},
+		{BlockQuads: 10, Dictionary: true},
+	} {
+		data := writeQuads(t, &opts, in)
+
+		// index records are not visible to stream readers
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", opts)
+		}
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		for range in {
+			if err = r.SkipQuad(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = r.SkipQuad(ctx); err == nil {
+			t.Fatal("expected EOF")
+		}
+
+		ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if ir.NumBlocks() != 11 || ir.NumQuads() != len(in) {
+			t.Fatalf("unexpected index: %d blocks, %d quads", ir.NumBlocks(), ir.NumQuads())
+		}
+		for i := 0; i < ir.NumBlocks(); i++ {
+			start := ir.BlockOrdinal(i)
+			end := start + 10
+			if end > len(in) {
+				end = len(in)
+			}
+			out, err = quad.ReadAll(ctx, ir.Block(i))
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(in[start:end], out) {
+				t.Fatalf("%+v: corrupted quads in block %d", opts, i)
+			}
+		}
+
+		out = out[:0]
+		ir.Reverse(ctx)(func(q quad.Quad, err error) bool {
+			if err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, q)
+			return true
+		})
+		if len(out) != len(in) {
+			t.Fatalf("unexpected number of quads: %d", len(out))
+		}
+		for i, q := range out {
+			if q != in[len(in)-1-i] {
+				t.Fatalf("%+v: unexpected quad %d: %v", opts, i, q)
+			}
+		}
+
+		n := 0
+		ir.Reverse(ctx)(func(q quad.Quad, err error) bool {
+			n++
+			return n < 15
+		})
+		if n != 15 {
+			t.Fatalf("iteration didn't stop: %d", n)
+		}
+	}
+
+	data := writeQuads(t, nil, in)
+	if _, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0); err == nil {
+		t.Fatal("expected an error for a file without index")
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	for _, c := range []struct {
+		opts       pquads.Options
+		checkpoint bool // write a checkpoint after 50 quads
+		exp        []int
+	}{
+		{opts: pquads.Options{}, exp: []int{0, 20, 40, 60, 80}},
+		{opts: pquads.Options{Strict: true}, exp: []int{0, 20, 40, 60, 80}},
+		{opts: pquads.Options{Full: true}, exp: []int{0, 15, 30, 45, 60, 75, 90}},
+		{opts: pquads.Options{Dictionary: true}, exp: []int{0}},
+		{opts: pquads.Options{Dictionary: true}, checkpoint: true, exp: []int{0, 50}},
+	} {
+		opts := c.opts
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, &opts)
+		if _, err := w.WriteQuads(ctx, in[:50]); err != nil {
+			t.Fatal(err)
+		}
+		if c.checkpoint {
+			if err := w.Checkpoint(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := w.WriteQuads(ctx, in[50:]); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+		ra := bytes.NewReader(data)
+
+		idx, err := pquads.BuildIndex(ra, int64(len(data)), 0, 15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := pquads.NewIndexedReaderFrom(ra, int64(len(data)), 0, idx)
+		if err != nil {
+			t.Fatal(err)
+		} else if r.NumQuads() != len(in) {
+			t.Fatalf("%+v: expected %d quads, got %d", opts, len(in), r.NumQuads())
+		}
+		var ords []int
+		for i := 0; i < r.NumBlocks(); i++ {
+			ords = append(ords, r.BlockOrdinal(i))
+		}
+		if !reflect.DeepEqual(ords, c.exp) {
+			t.Fatalf("%+v: unexpected blocks: %v", opts, ords)
+		}
+		for i := 0; i < r.NumBlocks(); i++ {
+			end := len(in)
+			if i+1 < r.NumBlocks() {
+				end = r.BlockOrdinal(i + 1)
+			}
+			got, err := quad.ReadAll(ctx, r.Block(i))
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(got, in[r.BlockOrdinal(i):end]) {
+				t.Fatalf("%+v: unexpected quads in block %d", opts, i)
+			}
+		}
+	}
+}
+
+func TestRechunk(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(105)
+	for _, opts := range []pquads.Options{
+		{BlockQuads: 5},
+		{BlockQuads: 5, Strict: true, Dictionary: true},
+		{Full: true, QuadHashes: true},
+	} {
+		src := writeQuads(t, &opts, in)
+		for _, c := range []struct {
+			size   int
+			blocks int
+		}{
+			{size: 50, blocks: 3},
+			{size: 200, blocks: 1},
+			{size: 0, blocks: 0},
+		} {
+			buf := bytes.NewBuffer(nil)
+			n, blocks, err := pquads.Rechunk(buf, bytes.NewReader(src), 0, c.size)
+			if err != nil {
+				t.Fatal(err)
+			} else if n != len(in) || blocks != c.blocks {
+				t.Fatalf("%+v, %d: unexpected result: %d quads, %d blocks", opts, c.size, n, blocks)
+			}
+			data := buf.Bytes()
+			r := pquads.NewReader(bytes.NewReader(data), 0)
+			out, err := quad.ReadAll(ctx, r)
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(in, out) {
+				t.Fatalf("%+v, %d: corrupted quads", opts, c.size)
+			}
+			rec, err := pquads.NewRecordReader(bytes.NewReader(data), 0).ReadRecord(ctx)
+			if err != nil {
+				t.Fatal(err)
+			} else if h := rec.Header; h.NotStrict == opts.Strict || h.Dictionary != opts.Dictionary ||
+				h.Full != opts.Full || h.Hashes != opts.QuadHashes {
+				t.Fatalf("%+v, %d: options are not preserved: %v", opts, c.size, h)
+			}
+			ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+			if c.size == 0 {
+				if err == nil {
+					t.Fatalf("%+v: expected no index", opts)
+				}
+				continue
+			} else if err != nil {
+				t.Fatal(err)
+			} else if ir.NumBlocks() != c.blocks {
+				t.Fatalf("%+v, %d: unexpected number of blocks: %d", opts, c.size, ir.NumBlocks())
+			}
+		}
+	}
+
+	// compression is restarted at each block
+	src := writeQuads(t, &pquads.Options{Compress: true}, in)
+	buf := bytes.NewBuffer(nil)
+	if _, _, err := pquads.Rechunk(buf, bytes.NewReader(src), 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(buf.Bytes()), 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(in, out) {
+		t.Fatal("corrupted quads")
+	} else if bytes.Count(buf.Bytes(), []byte{0x1f, 0x8b, 8}) < 10 {
+		t.Fatal("expected compression to be restarted")
+	}
+}
@@ -0,0 +1,191 @@
+package pquads
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cayleygraph/quad"
+)
+
+// RelabelBNodes replaces labels of blank nodes with labels derived from quads they occur in, so graphs that
+// differ only in blank node labels are usually relabeled identically. The order of quads is preserved.
+// The input slice is not modified.
+//
+// It's a bounded color refinement, not a complete canonicalization like URDNA2015:
+//
+//  1. The initial color of a blank node is a SHA-1 hash of the sorted list of all quads it occurs in,
+//     written in N-Quads form with the node itself replaced by "_:a" and other blank nodes replaced by "_:z".
+//  2. In each round, the color of a blank node is replaced by a hash of its previous color and the sorted list
+//     of its quads, written with the node replaced by "_:a" and other blank nodes replaced by their colors.
+//  3. Rounds are repeated until they no longer split blank nodes into more distinct colors, which takes
+//     at most as many rounds as there are blank nodes.
+//  4. Each blank node is labeled "c" followed by the first 16 bytes of its color in hex. Nodes that share
+//     a color are numbered in order of their first occurrence in the input, with a suffix like "_1".
+//
+// Blank nodes can only share a color if no number of rounds can tell them apart, as in symmetric graphs
+// (for example, a cycle of blank nodes). Labels of such nodes depend on the order of quads in the input,
+// although not on their original labels. Graphs that are not isomorphic may also get the same labels
+// in rare cases where color refinement cannot distinguish them.
+func RelabelBNodes(quads []quad.Quad) []quad.Quad {
+	// quads where each blank node occurs
+	occ := make(map[quad.BNode][]int)
+	var order []quad.BNode // blank nodes in order of first occurrence
+	for i, q := range quads {
+		for _, d := range quad.Directions {
+			b, ok := q.Get(d).(quad.BNode)
+			if !ok {
+				continue
+			}
+			l := occ[b]
+			if len(l) == 0 {
+				order = append(order, b)
+			}
+			if len(l) == 0 || l[len(l)-1] != i {
+				occ[b] = append(l, i)
+			}
+		}
+	}
+	out := make([]quad.Quad, len(quads))
+	copy(out, quads)
+	if len(order) == 0 {
+		return out
+	}
+	colors := make(map[quad.BNode]string, len(order))
+	for _, b := range order {
+		colors[b] = bnodeColor("", b, quads, occ[b], nil)
+	}
+	distinct := countColors(colors)
+	for range order {
+		next := make(map[quad.BNode]string, len(order))
+		for _, b := range order {
+			next[b] = bnodeColor(colors[b], b, quads, occ[b], colors)
+		}
+		n := countColors(next)
+		colors = next
+		if n == distinct {
+			break
+		}
+		distinct = n
+	}
+	labels := make(map[quad.BNode]quad.BNode, len(order))
+	seen := make(map[string]int, len(order))
+	for _, b := range order {
+		c := colors[b]
+		l := "c" + hex.EncodeToString([]byte(c[:16]))
+		if k := seen[c]; k > 0 {
+			l += "_" + strconv.Itoa(k)
+		}
+		seen[c]++
+		labels[b] = quad.BNode(l)
+	}
+	for i := range out {
+		for _, d := range quad.Directions {
+			if b, ok := out[i].Get(d).(quad.BNode); ok {
+				out[i].Set(d, labels[b])
+			}
+		}
+	}
+	return out
+}
+
+// bnodeColor computes a color of a blank node from its previous color and a given list of quads.
+// Other blank nodes are written as their colors, or as "_:z" if colors is nil.
+func bnodeColor(prev string, b quad.BNode, quads []quad.Quad, occ []int, colors map[quad.BNode]string) string {
+	lines := make([]string, 0, len(occ))
+	var sb strings.Builder
+	for _, i := range occ {
+		sb.Reset()
+		for _, d := range quad.Directions {
+			v := quad.StringOf(quads[i].Get(d))
+			if o, ok := quads[i].Get(d).(quad.BNode); ok {
+				switch {
+				case o == b:
+					v = "_:a"
+				case colors == nil:
+					v = "_:z"
+				default:
+					v = "_:" + hex.EncodeToString([]byte(colors[o]))
+				}
+			}
+			sb.WriteString(v)
+			sb.WriteByte(' ')
+		}
+		lines = append(lines, sb.String())
+	}
+	sort.Strings(lines)
+	h := sha1.New()
+	h.Write([]byte(prev))
+	for _, l := range lines {
+		h.Write([]byte(l))
+		h.Write([]byte{'\n'})
+	}
+	return string(h.Sum(nil))
+}
+
+func countColors(colors map[quad.BNode]string) int {
+	set := make(map[string]struct{}, len(colors))
+	for _, c := range colors {
+		set[c] = struct{}{}
+	}
+	return len(set)
+}
+
+var _ quad.WriteCloser = (*BNodeRelabelWriter)(nil)
+
+// BNodeRelabelWriter is a quad writer that relabels blank nodes deterministically (see RelabelBNodes).
+//
+// Since labels depend on all quads of the document, quads are buffered in memory and written to the destination
+// only on Close.
+type BNodeRelabelWriter struct {
+	dst  io.Writer
+	opts Options
+	buf  []quad.Quad
+	err  error
+}
+
+// NewBNodeRelabelWriter creates a writer that writes quads to dst with relabeled blank nodes once it's closed.
+func NewBNodeRelabelWriter(dst io.Writer, opts *Options) *BNodeRelabelWriter {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &BNodeRelabelWriter{dst: dst, opts: *opts}
+}
+
+func (w *BNodeRelabelWriter) WriteQuad(ctx context.Context, q quad.Quad) error {
+	if w.err != nil {
+		return w.err
+	} else if !q.IsValid() {
+		return quad.ErrInvalid
+	}
+	w.buf = append(w.buf, q)
+	return nil
+}
+
+func (w *BNodeRelabelWriter) WriteQuads(ctx context.Context, buf []quad.Quad) (int, error) {
+	for i, q := range buf {
+		if err := w.WriteQuad(ctx, q); err != nil {
+			return i, err
+		}
+	}
+	return len(buf), nil
+}
+
+// Close relabels blank nodes in all buffered quads and writes them to the destination.
+func (w *BNodeRelabelWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = errClosed
+	quads := RelabelBNodes(w.buf)
+	w.buf = nil
+	qw := NewWriter(w.dst, &w.opts)
+	if _, err := qw.WriteQuads(context.Background(), quads); err != nil {
+		return err
+	}
+	return qw.Close()
+}
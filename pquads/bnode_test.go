@@ -0,0 +1,84 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+// graphWithBNodes returns a graph with blank nodes labeled with a given prefix.
+func graphWithBNodes(pref string) []quad.Quad {
+	b := func(s string) quad.BNode { return quad.BNode(pref + s) }
+	return []quad.Quad{
+		{Subject: b("alice"), Predicate: quad.IRI("name"), Object: quad.String("Alice")},
+		{Subject: b("alice"), Predicate: quad.IRI("knows"), Object: b("bob")},
+		{Subject: b("bob"), Predicate: quad.IRI("name"), Object: quad.String("Bob")},
+		{Subject: b("bob"), Predicate: quad.IRI("knows"), Object: b("anon")},
+		{Subject: b("anon"), Predicate: quad.IRI("age"), Object: quad.Int(3), Label: b("g")},
+		// a symmetric cycle
+		{Subject: b("x"), Predicate: quad.IRI("next"), Object: b("y")},
+		{Subject: b("y"), Predicate: quad.IRI("next"), Object: b("x")},
+	}
+}
+
+func TestBNodeRelabelWriter(t *testing.T) {
+	ctx := context.Background()
+	write := func(quads []quad.Quad) []byte {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewBNodeRelabelWriter(buf, nil)
+		if _, err := w.WriteQuads(ctx, quads); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	a, b := write(graphWithBNodes("n")), write(graphWithBNodes("other_"))
+	if !bytes.Equal(a, b) {
+		t.Fatal("expected identical files")
+	}
+	out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(a), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := graphWithBNodes("n")
+	labels := make(map[quad.BNode]quad.BNode)
+	for i, q := range out {
+		for _, d := range quad.Directions {
+			v, ok := in[i].Get(d).(quad.BNode)
+			if !ok {
+				if q.Get(d) != in[i].Get(d) {
+					t.Fatalf("unexpected value in quad %d: %v", i, q)
+				}
+				continue
+			}
+			l := q.Get(d).(quad.BNode)
+			if prev, ok := labels[v]; ok && prev != l {
+				t.Fatalf("inconsistent labels for %v: %v vs %v", v, prev, l)
+			}
+			labels[v] = l
+		}
+	}
+	if labels["nx"] == labels["ny"] || !strings.HasPrefix(string(labels["ny"]), string(labels["nx"])+"_") {
+		t.Fatalf("unexpected labels of symmetric nodes: %v, %v", labels["nx"], labels["ny"])
+	}
+	uniq := make(map[quad.BNode]struct{})
+	for _, l := range labels {
+		uniq[l] = struct{}{}
+	}
+	if len(uniq) != len(labels) {
+		t.Fatalf("labels are not unique: %v", labels)
+	}
+
+	// a change in the graph changes labels of related nodes
+	changed := graphWithBNodes("n")
+	changed[2].Object = quad.String("Robert")
+	relabeled := pquads.RelabelBNodes(changed)
+	if relabeled[2].Subject == out[2].Subject || relabeled[0].Subject == out[0].Subject {
+		t.Fatal("expected labels to change")
+	}
+}
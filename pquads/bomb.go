@@ -0,0 +1,64 @@
+package pquads
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrDecompressionBomb is returned when a compressed stream exceeds decompression limits.
+var ErrDecompressionBomb = errors.New("pquads: decompression limits exceeded")
+
+var (
+	// DefaultMaxExpansionRatio is the default limit for the ratio of decompressed to compressed size.
+	// Regular pquads files rarely compress better than 20:1, while gzip can reach about 1000:1.
+	DefaultMaxExpansionRatio = 100.0
+	// DefaultMaxDecompressedSize is the default limit for the total decompressed size of a stream.
+	DefaultMaxDecompressedSize int64 = 64 << 30
+)
+
+// minRatioCheck is the decompressed size after which the expansion ratio is checked.
+// Small streams are always accepted, since the ratio is not meaningful for them.
+const minRatioCheck = 1 << 20
+
+// bombGuard limits the size of the decompressed stream.
+type bombGuard struct {
+	r        io.Reader
+	src      *countingReader
+	n        int64 // bytes decompressed
+	maxRatio float64
+	maxSize  int64
+}
+
+func (g *bombGuard) Read(p []byte) (int, error) {
+	var n int
+	var err error
+	if tm := g.src.tm; tm != nil {
+		// exclude the nested reads of compressed data
+		start, io := time.Now(), tm.IO.Total
+		n, err = g.r.Read(p)
+		tm.Decompress.add(time.Since(start) - (tm.IO.Total - io))
+	} else {
+		n, err = g.r.Read(p)
+	}
+	g.n += int64(n)
+	if g.maxSize > 0 && g.n > g.maxSize {
+		return n, ErrDecompressionBomb
+	}
+	if g.maxRatio > 0 && g.n > minRatioCheck && float64(g.n) > g.maxRatio*float64(g.src.Count()) {
+		return n, ErrDecompressionBomb
+	}
+	return n, err
+}
+
+// SetDecompressionLimits sets limits for compressed streams: the maximal ratio of decompressed to compressed size
+// and the maximal total decompressed size. Zero disables the limit. Reading fails with ErrDecompressionBomb if
+// any of the limits is exceeded. It has no effect for uncompressed streams.
+//
+// Limits default to DefaultMaxExpansionRatio and DefaultMaxDecompressedSize. The ratio is only checked after
+// the first megabyte of decompressed data.
+func (r *Reader) SetDecompressionLimits(maxRatio float64, maxSize int64) {
+	if r.guard != nil {
+		r.guard.maxRatio, r.guard.maxSize = maxRatio, maxSize
+	}
+}
@@ -0,0 +1,30 @@
+package pquads
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Op is a changelog operation for a quad.
+type Op int
+
+const (
+	OpAdd    = Op(iota) // quad is added
+	OpDelete            // quad is deleted (a tombstone)
+	OpUpsert            // quad is added, if it doesn't exist
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpAdd:
+		return "add"
+	case OpDelete:
+		return "delete"
+	case OpUpsert:
+		return "upsert"
+	}
+	return "Op(" + strconv.Itoa(int(op)) + ")"
+}
+
+// ErrTombstone is returned by Reader.ReadQuad if the changelog contains a delete operation.
+var ErrTombstone = errors.New("pquads: changelog contains a tombstone, use ReadChange")
@@ -0,0 +1,71 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestChangelog(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(6)
+	ops := []pquads.Op{
+		pquads.OpAdd, pquads.OpAdd, pquads.OpUpsert,
+		pquads.OpDelete, pquads.OpAdd, pquads.OpDelete,
+	}
+	for _, opts := range []pquads.Options{
+		{Changelog: true},
+		{Changelog: true, Strict: true},
+		{Changelog: true, Full: true},
+		{Changelog: true, Dictionary: true},
+	} {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, &opts)
+		for i, q := range in {
+			if err := w.WriteChange(ctx, q, ops[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data := buf.Bytes()
+
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		for i := range in {
+			q, op, err := r.ReadChange(ctx)
+			if err != nil {
+				t.Fatal(err)
+			} else if q != in[i] || op != ops[i] {
+				t.Fatalf("%+v: unexpected change %d: %v %v", opts, i, op, q)
+			}
+		}
+		if _, _, err := r.ReadChange(ctx); err != io.EOF {
+			t.Fatalf("expected EOF, got: %v", err)
+		}
+
+		r = pquads.NewReader(bytes.NewReader(data), 0)
+		for i := 0; i < 3; i++ {
+			if q, err := r.ReadQuad(ctx); err != nil {
+				t.Fatal(err)
+			} else if q != in[i] {
+				t.Fatalf("unexpected quad %d: %v", i, q)
+			}
+		}
+		if _, err := r.ReadQuad(ctx); err != pquads.ErrTombstone {
+			t.Fatalf("expected tombstone error, got: %v", err)
+		}
+
+		r = pquads.NewReader(bytes.NewReader(data), 0)
+		for range in {
+			if err := r.SkipQuad(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	w := pquads.NewWriter(io.Discard, nil)
+	if err := w.WriteChange(ctx, in[0], pquads.OpDelete); err == nil {
+		t.Fatal("expected an error for delete without changelog")
+	}
+}
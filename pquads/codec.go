@@ -0,0 +1,130 @@
+package pquads
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cayleygraph/quad"
+)
+
+// ValueEncoder encodes a typed literal into a compact binary form.
+type ValueEncoder func(v quad.TypedString) ([]byte, error)
+
+// ValueDecoder decodes a value from the binary form produced by ValueEncoder.
+// It may return any quad.Value, not only a quad.TypedString.
+type ValueDecoder func(data []byte) (quad.Value, error)
+
+type valueCodec struct {
+	enc ValueEncoder
+	dec ValueDecoder
+}
+
+var (
+	codecsMu    sync.RWMutex
+	valueCodecs = make(map[quad.IRI]valueCodec)
+)
+
+// RegisterValueCodec registers a codec for typed literals with a given datatype. It replaces any codec
+// registered for the same datatype. If both enc and dec are nil, the codec for the datatype will be removed.
+//
+// If an encoder is set, writers encode all quad.TypedString values of this datatype with it.
+// If it's not set, values are written as regular typed strings.
+//
+// If a decoder is set, readers use it to decode values written by the encoder. If it's not set or if it fails,
+// readers fail with an error, thus files must be read with the same codecs that were registered when writing them.
+func RegisterValueCodec(dataType quad.IRI, enc ValueEncoder, dec ValueDecoder) {
+	dataType = dataType.Full()
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	if enc == nil && dec == nil {
+		delete(valueCodecs, dataType)
+	} else {
+		valueCodecs[dataType] = valueCodec{enc: enc, dec: dec}
+	}
+}
+
+func getValueCodec(dataType quad.IRI) (valueCodec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	if len(valueCodecs) == 0 {
+		return valueCodec{}, false
+	}
+	c, ok := valueCodecs[dataType.Full()]
+	return c, ok
+}
+
+// encodeValues replaces typed literals in the message with values encoded by registered codecs.
+func encodeValues(m interface{}, q quad.Quad) error {
+	var err error
+	fix := func(p **Value, v quad.Value) {
+		ts, ok := v.(quad.TypedString)
+		if err != nil || *p == nil || !ok {
+			return
+		} else if _, ok := (*p).Value.(*Value_Ref); ok {
+			return
+		}
+		c, ok := getValueCodec(ts.Type)
+		if !ok || c.enc == nil {
+			return
+		}
+		var data []byte
+		if data, err = c.enc(ts); err != nil {
+			err = fmt.Errorf("cannot encode %v: %w", ts, err)
+			return
+		}
+		*p = &Value{Value: &Value_Codec{Codec: &Value_Encoded{Type: string(ts.Type), Data: data}}}
+	}
+	switch m := m.(type) {
+	case *WireQuad:
+		fix(&m.Subject, q.Subject)
+		fix(&m.Predicate, q.Predicate)
+		fix(&m.Object, q.Object)
+		fix(&m.Label, q.Label)
+	case *StrictQuad:
+		fix(&m.Object, q.Object)
+	}
+	return err
+}
+
+// ToNative decodes the value with a registered codec. It returns nil if there is no decoder for the datatype,
+// or if the decoder fails. Readers return an error in this case.
+func (m *Value_Encoded) ToNative() quad.Value {
+	v, _ := m.decode()
+	return v
+}
+
+// decode decodes the value with a registered codec.
+func (m *Value_Encoded) decode() (quad.Value, error) {
+	if m == nil {
+		return nil, errors.New("empty encoded value")
+	}
+	c, ok := getValueCodec(quad.IRI(m.Type))
+	if !ok || c.dec == nil {
+		return nil, fmt.Errorf("no value codec for datatype <%s>", m.Type)
+	}
+	v, err := c.dec(m.Data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode a value of datatype <%s>: %w", m.Type, err)
+	} else if v == nil {
+		return nil, fmt.Errorf("value codec for datatype <%s> returned no value", m.Type)
+	}
+	return v, nil
+}
+
+// nativeValue converts a value to quad.Value like ToNative, but fails for encoded values that cannot be decoded.
+func nativeValue(v *Value) (quad.Value, error) {
+	if c, ok := v.GetValue().(*Value_Codec); ok {
+		return c.Codec.decode()
+	}
+	return v.ToNative(), nil
+}
+
+// checkCodec verifies that an encoded value can be decoded.
+func checkCodec(v *Value) error {
+	if c, ok := v.GetValue().(*Value_Codec); ok {
+		_, err := c.Codec.decode()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+const pointType = quad.IRI("http://example.com/point")
+
+func encodePoint(v quad.TypedString) ([]byte, error) {
+	var x, y int64
+	if _, err := fmt.Sscanf(string(v.Value), "%d,%d", &x, &y); err != nil {
+		return nil, err
+	}
+	buf := binary.AppendVarint(nil, x)
+	return binary.AppendVarint(buf, y), nil
+}
+
+func decodePoint(data []byte) (quad.Value, error) {
+	x, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid point")
+	}
+	y, m := binary.Varint(data[n:])
+	if m <= 0 {
+		return nil, fmt.Errorf("invalid point")
+	}
+	return quad.TypedString{Value: quad.String(fmt.Sprintf("%d,%d", x, y)), Type: pointType}, nil
+}
+
+func TestValueCodec(t *testing.T) {
+	ctx := context.Background()
+	var in []quad.Quad
+	for i := 0; i < 20; i++ {
+		p := quad.TypedString{Value: quad.String(fmt.Sprintf("%d,%d", i*1000, -i)), Type: pointType}
+		in = append(in, quad.Quad{Subject: quad.IRI(fmt.Sprint("s", i)), Predicate: quad.IRI("loc"), Object: p})
+	}
+	plain := writeQuads(t, nil, in)
+
+	pquads.RegisterValueCodec(pointType, encodePoint, decodePoint)
+	defer pquads.RegisterValueCodec(pointType, nil, nil)
+	for _, opts := range []pquads.Options{{}, {Strict: true}, {Dictionary: true}} {
+		data := writeQuads(t, &opts, in)
+		if len(data) >= len(plain) {
+			t.Fatalf("%+v: expected smaller output: %d vs %d", opts, len(data), len(plain))
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", opts)
+		}
+	}
+
+	w := pquads.NewWriter(bytes.NewBuffer(nil), nil)
+	bad := quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("loc"), Object: quad.TypedString{Value: "x", Type: pointType}}
+	if err := w.WriteQuad(ctx, bad); err == nil {
+		t.Fatal("expected an encoding error")
+	}
+
+	// without a decoder or if it fails, the reader returns an error instead of the encoded data
+	data := writeQuads(t, nil, in[:1])
+	fail := func(data []byte) (quad.Value, error) {
+		return nil, fmt.Errorf("invalid point")
+	}
+	for _, dec := range []pquads.ValueDecoder{nil, fail} {
+		pquads.RegisterValueCodec(pointType, encodePoint, dec)
+		for _, opts := range []pquads.Options{{}, {Full: true}} {
+			data := writeQuads(t, &opts, in[:1])
+			if _, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0)); err == nil {
+				t.Fatalf("%+v: expected a decoding error", opts)
+			}
+		}
+		if _, err := pquads.DecodeStandalone(rawRecords(t, data)[0], false); err == nil {
+			t.Fatal("expected a decoding error for a standalone record")
+		}
+	}
+}
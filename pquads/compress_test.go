@@ -0,0 +1,121 @@
+package pquads_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+// gzipMembers returns the number of members in a gzip stream.
+func gzipMembers(t testing.TB, data []byte) int {
+	br := bytes.NewReader(data)
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for {
+		zr.Multistream(false)
+		if _, err = io.Copy(io.Discard, zr); err != nil {
+			t.Fatal(err)
+		}
+		n++
+		if err = zr.Reset(br); err == io.EOF {
+			return n
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	for _, c := range []struct {
+		opts    pquads.Options
+		members int
+	}{
+		{pquads.Options{Compress: true}, 1},
+		{pquads.Options{Compress: true, CompressResetQuads: 10}, 10},
+		{pquads.Options{Compress: true, CompressResetQuads: 15, BlockQuads: 20}, 10},
+	} {
+		data := writeQuads(t, &c.opts, in)
+		if n := gzipMembers(t, data); n != c.members {
+			t.Fatalf("%+v: unexpected number of gzip members: %d", c.opts, n)
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", c.opts)
+		}
+		if path, err := exec.LookPath("gzip"); err == nil {
+			cmd := exec.Command(path, "-t")
+			cmd.Stdin = bytes.NewReader(data)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("gzip -t failed: %v\n%s", err, out)
+			}
+		}
+	}
+}
+
+func TestCompressReset(t *testing.T) {
+	in := makeQuads(100)
+	opts := &pquads.Options{Compress: true, CompressResetQuads: 10}
+	data1 := writeQuads(t, opts, in)
+	in[25].Object = quad.String("changed")
+	data2 := writeQuads(t, opts, in)
+	// only the third member is different
+	pref, suff := 0, 0
+	for pref < len(data1) && pref < len(data2) && data1[pref] == data2[pref] {
+		pref++
+	}
+	for suff < len(data1) && suff < len(data2) && data1[len(data1)-1-suff] == data2[len(data2)-1-suff] {
+		suff++
+	}
+	if diff := len(data2) - pref - suff; diff <= 0 || diff > len(data2)/5 {
+		t.Fatalf("unexpected difference: %d of %d bytes", diff, len(data2))
+	}
+}
+
+func TestDecompressionBomb(t *testing.T) {
+	ctx := context.Background()
+	big := quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String(strings.Repeat("a", 16<<20))}
+	data := writeQuads(t, &pquads.Options{Compress: true}, []quad.Quad{big})
+	if len(data)*500 > 16<<20 {
+		t.Fatalf("compression ratio is too low: %d bytes", len(data))
+	}
+	r := pquads.NewReader(bytes.NewReader(data), 32<<20)
+	if _, err := r.ReadQuad(ctx); err != pquads.ErrDecompressionBomb {
+		t.Fatalf("expected decompression bomb error, got: %v", err)
+	}
+	r = pquads.NewReader(bytes.NewReader(data), 32<<20)
+	r.SetDecompressionLimits(0, 0)
+	if q, err := r.ReadQuad(ctx); err != nil {
+		t.Fatal(err)
+	} else if q != big {
+		t.Fatal("corrupted quad")
+	}
+
+	in := makeQuads(50000)
+	data = writeQuads(t, &pquads.Options{Compress: true}, in)
+	out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(out) != len(in) {
+		t.Fatalf("unexpected number of quads: %d", len(out))
+	}
+	r = pquads.NewReader(bytes.NewReader(data), 0)
+	r.SetDecompressionLimits(0, 100<<10)
+	if _, err = quad.ReadAll(ctx, r); err != pquads.ErrDecompressionBomb {
+		t.Fatalf("expected decompression bomb error, got: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package pquads
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cayleygraph/quad/pquads/pio"
+)
+
+// Concat writes a single pquads file to dst containing all quads from the bodies, in order.
+//
+// Each body must be written with the NoHeader option and the same Full, Strict, Dictionary, Changelog,
+// QuadHashes, RollingCRC, SubjectGroups, Sources, LangDictionary, LangTags, TimeDeltas and TimeBase options
+// as opts. Rolling checksums in checkpoints are recalculated for the new file.
+// Quads are copied without decoding them. A checkpoint is written between bodies to reset the state carried
+// over from the previous body, and ordinals of checkpoints in bodies are adjusted to the position in the file.
+// Bodies with id-based quads or a shared dictionary are not supported. See NewReader for the description of maxSize.
+func Concat(dst io.Writer, opts *Options, maxSize int, bodies ...io.Reader) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	hopts := *opts
+	hopts.NoHeader = false
+	hopts.BlockQuads = 0
+	w := NewWriter(dst, &hopts)
+	if w.err != nil {
+		return w.err
+	}
+	for i, body := range bodies {
+		pr := pio.NewReader(body, maxSize).(pio.RawReader)
+		var h Header
+		if err := pr.ReadMsg(&h); err == io.EOF {
+			return fmt.Errorf("body %d: empty input", i)
+		} else if err != nil {
+			return fmt.Errorf("body %d: cannot read header: %w", i, err)
+		}
+		if h.Ids {
+			return fmt.Errorf("body %d: id-based quads are not supported", i)
+		} else if h.SharedDictionary {
+			return fmt.Errorf("body %d: shared dictionary is not supported", i)
+		} else if h.Full != opts.Full || h.NotStrict == opts.Strict || h.Dictionary != opts.Dictionary ||
+			h.Changelog != opts.Changelog || h.Hashes != opts.QuadHashes || h.RollingCrc != opts.RollingCRC ||
+			h.SubjectGroups != opts.SubjectGroups || h.Sources != opts.Sources ||
+			h.LangDictionary != opts.LangDictionary || h.TimeDeltas != opts.TimeDeltas {
+			return fmt.Errorf("body %d: options mismatch: full=%v, strict=%v, dictionary=%v, changelog=%v, "+
+				"hashes=%v, rolling_crc=%v, subject_groups=%v, sources=%v, lang_dictionary=%v, time_deltas=%v",
+				i, h.Full, !h.NotStrict, h.Dictionary, h.Changelog, h.Hashes, h.RollingCrc, h.SubjectGroups, h.Sources,
+				h.LangDictionary, h.TimeDeltas)
+		} else if !equalLangTags(h.LangTags, w.opts.LangTags) {
+			return fmt.Errorf("body %d: language tags mismatch: %q", i, h.LangTags)
+		} else if tb := timeBase(h.TimeBase); opts.TimeDeltas && !tb.Equal(opts.TimeBase) {
+			return fmt.Errorf("body %d: time base mismatch: %v", i, tb)
+		}
+		if i != 0 {
+			if err := w.Checkpoint(); err != nil {
+				return err
+			}
+		}
+		base := w.n
+		for {
+			data, err := pr.ReadRaw()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return fmt.Errorf("body %d: %w", i, err)
+			}
+			if hasField(data, indexField) || hasField(data, footerField) {
+				continue
+			} else if hasField(data, checkpointField) {
+				var pq WireQuad
+				if err = pq.UnmarshalVT(data); err != nil {
+					return fmt.Errorf("body %d: %w", i, err)
+				}
+				pq.Checkpoint.Ordinal += uint64(base)
+				pq.Checkpoint.Crc = w.RollingSum()
+				if data, err = pq.MarshalVT(); err != nil {
+					return err
+				}
+			} else if hasField(data, sourceField) {
+				// sources are not quads, and their ids are reset by the checkpoint before the body
+			} else if n := countField(data, groupField); n != 0 {
+				w.n += n
+			} else {
+				w.n++
+			}
+			n, err := w.pw.WriteRaw(data)
+			w.off += int64(n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return w.Close()
+}
@@ -0,0 +1,136 @@
+package pquads
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cayleygraph/quad"
+)
+
+// Quad batches are sent over a connection as complete pquads documents, each prefixed with its length
+// encoded as uvarint. The connection stays open between batches and can be used in both directions.
+
+// ConnWriter sends batches of quads over a connection. It's not safe for concurrent use.
+type ConnWriter struct {
+	conn net.Conn
+	opts Options
+	buf  bytes.Buffer
+}
+
+// NewConnWriter creates a writer that sends batches of quads to conn, encoded with a given options.
+// Options that make the output not self-contained (like NoHeader) are not allowed.
+func NewConnWriter(conn net.Conn, opts *Options) *ConnWriter {
+	w := &ConnWriter{conn: conn}
+	if opts != nil {
+		w.opts = *opts
+	}
+	return w
+}
+
+// WriteBatch encodes quads into a single pquads document and writes it to the connection.
+//
+// The context deadline is used as a write deadline, and the write is interrupted if the context is cancelled.
+// The connection should not be used after an interrupted write, since the document may be sent partially.
+func (w *ConnWriter) WriteBatch(ctx context.Context, quads []quad.Quad) error {
+	if w.opts.NoHeader {
+		return fmt.Errorf("cannot send documents without a header")
+	}
+	var pref [binary.MaxVarintLen64]byte
+	w.buf.Reset()
+	// reserve space for the length prefix
+	w.buf.Write(pref[:])
+	qw := NewWriter(&w.buf, &w.opts)
+	if _, err := qw.WriteQuads(ctx, quads); err != nil {
+		return err
+	} else if err = qw.Close(); err != nil {
+		return err
+	}
+	data := w.buf.Bytes()
+	n := binary.PutUvarint(pref[:], uint64(len(data)-len(pref)))
+	data = data[len(pref)-n:]
+	copy(data, pref[:n])
+
+	stop := watchDeadline(ctx, w.conn.SetWriteDeadline)
+	_, err := w.conn.Write(data)
+	return stop(err)
+}
+
+// ConnReader receives batches of quads sent by ConnWriter. It's not safe for concurrent use.
+type ConnReader struct {
+	conn    net.Conn
+	br      *bufio.Reader
+	maxSize int
+}
+
+// NewConnReader creates a reader for batches of quads sent over conn. See NewReader for the description of maxSize.
+func NewConnReader(conn net.Conn, maxSize int) *ConnReader {
+	return &ConnReader{conn: conn, br: bufio.NewReader(conn), maxSize: maxSize}
+}
+
+// ReadBatch waits for the next document on the connection and returns all its quads.
+// It returns io.EOF if the connection was closed between documents.
+//
+// The context deadline is used as a read deadline, and the read is interrupted if the context is cancelled.
+// The connection should not be used after an interrupted read.
+func (r *ConnReader) ReadBatch(ctx context.Context) ([]quad.Quad, error) {
+	stop := watchDeadline(ctx, r.conn.SetReadDeadline)
+	quads, err := r.readBatch(ctx)
+	return quads, stop(err)
+}
+
+func (r *ConnReader) readBatch(ctx context.Context) ([]quad.Quad, error) {
+	size, err := binary.ReadUvarint(r.br)
+	if err != nil {
+		return nil, err
+	}
+	lr := &io.LimitedReader{R: r.br, N: int64(size)}
+	qr := NewReader(lr, r.maxSize)
+	if qr.err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	quads, err := quad.ReadAll(ctx, qr)
+	if err == nil && lr.N != 0 {
+		// the connection was closed in the middle of the document
+		err = io.ErrUnexpectedEOF
+	}
+	return quads, err
+}
+
+// watchDeadline sets the deadline from the context and resets it when the returned function is called.
+// It also interrupts the I/O if the context is cancelled, in which case the context error is returned instead.
+func watchDeadline(ctx context.Context, set func(t time.Time) error) func(err error) error {
+	dl, _ := ctx.Deadline()
+	_ = set(dl)
+	done := ctx.Done()
+	if done == nil {
+		return func(err error) error { return err }
+	}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-done:
+			// force pending I/O to fail immediately
+			_ = set(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	return func(err error) error {
+		close(stop)
+		wg.Wait()
+		_ = set(time.Time{})
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+}
@@ -0,0 +1,68 @@
+package pquads_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestConn(t *testing.T) {
+	ctx := context.Background()
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	batches := [][]int{{0, 10}, {10, 11}, {11, 11}, {11, 50}}
+	in := makeQuads(50)
+
+	errc := make(chan error, 1)
+	go func() {
+		defer c1.Close()
+		w := pquads.NewConnWriter(c1, &pquads.Options{Strict: true})
+		for _, b := range batches {
+			if err := w.WriteBatch(ctx, in[b[0]:b[1]]); err != nil {
+				errc <- err
+				return
+			}
+		}
+		errc <- nil
+	}()
+	r := pquads.NewConnReader(c2, 0)
+	for _, b := range batches {
+		out, err := r.ReadBatch(ctx)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(out) != b[1]-b[0] || (len(out) != 0 && !reflect.DeepEqual(in[b[0]:b[1]], out)) {
+			t.Fatalf("unexpected batch: %v", out)
+		}
+	}
+	if _, err := r.ReadBatch(ctx); err != io.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnContext(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	r := pquads.NewConnReader(c2, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := r.ReadBatch(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline error, got: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	w := pquads.NewConnWriter(c1, nil)
+	if err := w.WriteBatch(ctx, makeQuads(1)); err != context.Canceled {
+		t.Fatalf("expected cancellation error, got: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package pquads
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads/pio"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The rolling CRC (see Options.RollingCRC) is a CRC-32C of the uncompressed stream, starting from the first byte
+// of the file magic. It's chained across records: the sum stored in a checkpoint covers all bytes before the
+// checkpoint record, including previous checkpoints with their sums, thus verifying the last checkpoint
+// also verifies the integrity of all the data before it.
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrChecksum is returned if the rolling CRC of the stream doesn't match the one stored in a checkpoint.
+var ErrChecksum = errors.New("pquads: rolling checksum mismatch")
+
+// crcWriter maintains the rolling CRC of all bytes written.
+type crcWriter struct {
+	w   io.Writer
+	sum uint32
+}
+
+func (w *crcWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.sum = crc32.Update(w.sum, crcTable, p[:n])
+	return n, err
+}
+
+// RollingSum returns the rolling CRC of all bytes written so far. It returns 0 if Options.RollingCRC is not set.
+func (w *Writer) RollingSum() uint32 {
+	if w.crc == nil {
+		return 0
+	}
+	return w.crc.sum
+}
+
+// finalCheckpoint writes a checkpoint that covers the whole stream with the rolling CRC.
+func (w *Writer) finalCheckpoint() error {
+	if err := w.Checkpoint(); err != nil {
+		return err
+	}
+	if last := len(w.blocks) - 1; last > 0 && w.blocks[last].Ordinal == uint64(w.n) {
+		// don't start an empty block; the checkpoint is the end of the previous one
+		w.blocks = w.blocks[:last]
+	}
+	return nil
+}
+
+// readRaw reads the next raw record, updating the offset and the rolling CRC.
+func (r *Reader) readRaw() ([]byte, error) {
+	data, err := r.pr.ReadRaw()
+	if err != nil {
+		return data, err
+	}
+	r.off += int64(protowire.SizeBytes(len(data)))
+	r.last = data
+	if r.crc {
+		r.updateSum(data)
+	}
+	return data, nil
+}
+
+// updateSum adds a record with its length prefix to the rolling CRC.
+func (r *Reader) updateSum(data []byte) {
+	r.cpSum = r.sum
+	r.sum = recordSum(r.sum, data)
+}
+
+// recordSum adds a record with its length prefix to a CRC-32C.
+func recordSum(sum uint32, data []byte) uint32 {
+	var pref [binary.MaxVarintLen64]byte
+	sum = crc32.Update(sum, crcTable, protowire.AppendVarint(pref[:0], uint64(len(data))))
+	return crc32.Update(sum, crcTable, data)
+}
+
+// VerifyRollingSum reads a pquads stream written with Options.RollingCRC and verifies the rolling CRC stored
+// in each checkpoint. It fails with ErrChecksum on the first mismatch. See NewReader for the description of maxSize.
+//
+// It also fails if the stream doesn't end with a checkpoint (followed only by the block index), which happens
+// if the writer was not closed. On success, it returns the rolling CRC of the whole stream and the number of quads.
+func VerifyRollingSum(r io.Reader, maxSize int) (sum uint32, quads int, err error) {
+	qr := NewReader(r, maxSize)
+	defer qr.Close()
+	if err = qr.verifyRollingSum(); err != nil {
+		return 0, 0, err
+	}
+	return qr.sum, qr.n, nil
+}
+
+// verifyRollingSum reads the rest of the stream, verifying the rolling CRC.
+func (r *Reader) verifyRollingSum() error {
+	if r.err != nil {
+		return r.err
+	} else if !r.crc {
+		return errors.New("pquads file has no rolling checksum")
+	}
+	ctx := context.Background()
+	for {
+		err := r.SkipQuad(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("quad %d: %w", r.n, err)
+		}
+	}
+	if err := r.VerifyEnd(); err != nil {
+		return err
+	} else if !r.lastCp {
+		return errors.New("pquads stream doesn't end with a checkpoint, the writer was probably not closed")
+	}
+	return nil
+}
+
+// NewAppendWriter opens an existing pquads file written with Options.RollingCRC for appending.
+// The file is verified with VerifyRollingSum first, thus quads are never appended to corrupted data.
+// See NewReader for the description of maxSize.
+//
+// The returned writer uses the options of the file, and continues its rolling CRC and quad ordinals.
+// Like any other writer, it must be closed to write the final checkpoint. Compressed files and files
+// with a block index cannot be appended to.
+func NewAppendWriter(f io.ReadWriteSeeker, maxSize int) (*Writer, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := NewReader(f, maxSize)
+	if r.err != nil {
+		return nil, r.err
+	} else if r.zr != nil {
+		return nil, errors.New("cannot append to compressed pquads file")
+	}
+	if err := r.verifyRollingSum(); err != nil {
+		return nil, err
+	} else if r.footer {
+		return nil, errors.New("cannot append to pquads file with a block index")
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	cw := &crcWriter{w: f, sum: r.sum}
+	w := &Writer{pw: pio.NewWriter(cw).(pio.RawWriter), opts: r.opts, n: r.n, off: size, crc: cw}
+	if w.opts.Dictionary {
+		w.dict = make(map[quad.Value]uint64)
+	}
+	w.resetLangs()
+	w.resetTime()
+	return w, nil
+}
@@ -0,0 +1,140 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestRollingCRC(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(50)
+	for _, opts := range []*pquads.Options{
+		{RollingCRC: true},
+		{RollingCRC: true, Full: true},
+		{RollingCRC: true, Dictionary: true},
+		{RollingCRC: true, BlockQuads: 20},
+		{RollingCRC: true, Compress: true},
+	} {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		if _, err := w.WriteQuads(ctx, quads[:25]); err != nil {
+			t.Fatal(err)
+		} else if err = w.Checkpoint(); err != nil {
+			t.Fatal(err)
+		} else if _, err = w.WriteQuads(ctx, quads[25:]); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+
+		got, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		} else if !reflect.DeepEqual(got, quads) {
+			t.Fatalf("%+v: unexpected quads", opts)
+		}
+		sum, n, err := pquads.VerifyRollingSum(bytes.NewReader(data), 0)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		} else if n != len(quads) {
+			t.Fatalf("%+v: expected %d quads, got %d", opts, len(quads), n)
+		} else if sum != w.RollingSum() {
+			t.Fatalf("%+v: rolling sum mismatch: %x vs %x", opts, sum, w.RollingSum())
+		}
+		if opts.Compress {
+			continue
+		}
+
+		// corrupt a value without breaking the encoding
+		bad := append([]byte{}, data...)
+		i := bytes.Index(bad, []byte("object 30"))
+		bad[i+len("object 30")-1] = '9'
+		if _, _, err = pquads.VerifyRollingSum(bytes.NewReader(bad), 0); !errors.Is(err, pquads.ErrChecksum) {
+			t.Fatalf("%+v: expected checksum error, got: %v", opts, err)
+		}
+		if _, err = quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(bad), 0)); !errors.Is(err, pquads.ErrChecksum) {
+			t.Fatalf("%+v: expected checksum error, got: %v", opts, err)
+		}
+	}
+
+	// not closed
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{RollingCRC: true})
+	if _, err := w.WriteQuads(ctx, quads); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := pquads.VerifyRollingSum(buf, 0); err == nil {
+		t.Fatal("expected an error for a stream without the final checkpoint")
+	}
+}
+
+func TestAppendWriter(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(60)
+	f, err := os.Create(filepath.Join(t.TempDir(), "quads.pq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := pquads.NewWriter(f, &pquads.Options{RollingCRC: true, Dictionary: true})
+	if _, err = w.WriteQuads(ctx, quads[:20]); err != nil {
+		t.Fatal(err)
+	} else if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 20; i < len(quads); i += 20 {
+		w, err = pquads.NewAppendWriter(f, 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err = w.WriteQuads(ctx, quads[i:i+20]); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := quad.ReadAll(ctx, pquads.NewReader(f, 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, quads) {
+		t.Fatalf("unexpected quads:\n%v\nvs\n%v", got, quads)
+	}
+
+	// corrupted files are not appended to
+	if _, err = f.WriteAt([]byte("x"), 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pquads.NewAppendWriter(f, 0); err == nil {
+		t.Fatal("expected an error for a corrupted file")
+	}
+}
+
+func TestConcatRollingCRC(t *testing.T) {
+	all := makeQuads(30)
+	opts := &pquads.Options{NoHeader: true, RollingCRC: true}
+	var bodies []io.Reader
+	for i := 0; i < len(all); i += 10 {
+		bodies = append(bodies, bytes.NewReader(writeQuads(t, opts, all[i:i+10])))
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := pquads.Concat(buf, &pquads.Options{RollingCRC: true}, 0, bodies...); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, err := pquads.VerifyRollingSum(buf, 0); err != nil {
+		t.Fatal(err)
+	} else if n != len(all) {
+		t.Fatalf("expected %d quads, got %d", len(all), n)
+	}
+}
@@ -0,0 +1,198 @@
+package pquads
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads/pio"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// cursorVersion is the version of the cursor encoding, see Cursor.Version.
+const cursorVersion = 1
+
+// ErrCursorMismatch is returned by ResumeReader if the cursor was taken from a different stream.
+var ErrCursorMismatch = errors.New("pquads: cursor doesn't match the stream")
+
+var errCursorCompressed = errors.New("pquads: cursors are not supported for compressed streams")
+
+// headerSum returns a CRC-32C of the magic and version prefix followed by the header record.
+func headerSum(pref, data []byte) uint32 {
+	return recordSum(crc32.Update(0, crcTable, pref), data)
+}
+
+// Cursor returns an opaque token for the current read position. The token can be stored and passed
+// to ResumeReader later to continue reading the same stream from this position.
+//
+// The token contains the offset of the next record and the state needed to decode the following quads:
+// the previous quad values, the dictionaries, the source names and the rolling CRC. Thus, its size grows
+// with the size of the dictionary. Settings of the reader, like the allocator or the value pool, are not stored.
+//
+// The token is bound to the stream by checksums of its header and of the last record read before the position.
+// Cursors are not supported for compressed streams, since they cannot be read from an arbitrary offset.
+// It fails if the reader failed with an error other than io.EOF.
+func (r *Reader) Cursor() ([]byte, error) {
+	if r.err != nil && r.err != io.EOF {
+		return nil, r.err
+	} else if r.zr != nil {
+		return nil, errCursorCompressed
+	} else if r.hsize == 0 {
+		return nil, errors.New("pquads: no header to take a cursor")
+	}
+	c := &Cursor{
+		Version:       cursorVersion,
+		Offset:        uint64(r.off),
+		HeaderCrc:     r.hsum,
+		MaxSize:       uint64(r.maxSize),
+		Ordinal:       uint64(r.n),
+		Subject:       cursorValue(r.s),
+		Predicate:     cursorValue(r.p),
+		Object:        cursorValue(r.o),
+		Sources:       r.srcNames,
+		Source:        r.srcName,
+		HasCheckpoint: r.hasCp,
+		Checkpoint:    uint64(r.cp),
+		GroupLeft:     uint64(len(r.wgroup) + len(r.sgroup)),
+	}
+	if r.last != nil {
+		c.LastSize = uint64(protowire.SizeBytes(len(r.last)))
+		c.LastCrc = recordSum(0, r.last)
+	}
+	if len(r.dict) != 0 {
+		c.Dict = make([]*Value, len(r.dict))
+		for i, v := range r.dict {
+			c.Dict[i] = cursorValue(v)
+		}
+	}
+	langs, lastTime, hasTime := r.langs, r.lastTime, r.hasTime
+	if c.GroupLeft != 0 {
+		// the group record is decoded again on resume, thus save the state preceding it
+		langs, lastTime, hasTime = r.langs[:r.recLangs], r.recTime, r.recHasTime
+	}
+	if r.opts.LangDictionary {
+		c.LangTags = langs[len(r.opts.LangTags):]
+	}
+	if hasTime {
+		c.LastTime = MakeValue(quad.Time(lastTime))
+	}
+	if r.crc {
+		c.Sum, c.CpSum, c.LastCheckpoint = r.sum, r.cpSum, r.lastCp
+	}
+	return c.MarshalVT()
+}
+
+// cursorValue encodes a value for a cursor, preserving invalid UTF-8 strings.
+func cursorValue(v quad.Value) *Value {
+	if !validUTF8(v) {
+		return makeBinaryValue(v)
+	}
+	return MakeValue(v)
+}
+
+// ResumeReader creates a reader that continues reading a stream from a position returned by Reader.Cursor.
+// The stream must be the same one the cursor was taken from, starting at offset zero of rs. Data appended
+// to the stream after the cursor was taken is read as usual.
+//
+// It fails with ErrCursorMismatch if the header of the stream or the record preceding the position
+// doesn't match the cursor.
+func ResumeReader(rs io.ReadSeeker, cursor []byte) (*Reader, error) {
+	var c Cursor
+	if err := c.UnmarshalVT(cursor); err != nil {
+		return nil, fmt.Errorf("invalid pquads cursor: %w", err)
+	} else if c.Version != cursorVersion {
+		return nil, fmt.Errorf("unsupported pquads cursor version: %d", c.Version)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	hr, h := openReader(rs, int(c.MaxSize))
+	if hr.err != nil {
+		return nil, hr.err
+	} else if hr.zr != nil {
+		return nil, errCursorCompressed
+	} else if h.Ids {
+		return nil, fmt.Errorf("pquads file contains id-based quads, use NewIDReader")
+	} else if hr.hsum != c.HeaderCrc {
+		return nil, fmt.Errorf("%w: different header", ErrCursorMismatch)
+	}
+	off := int64(c.Offset)
+	start := off - int64(c.LastSize)
+	if start < hr.off {
+		return nil, fmt.Errorf("%w: invalid offset %d", ErrCursorMismatch, off)
+	}
+	if _, err := rs.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := &Reader{
+		src:  &countingReader{r: rs},
+		opts: hr.opts, version: hr.version, crc: hr.crc, shared: hr.shared,
+		off: off, hsize: hr.hsize, hsum: hr.hsum, maxSize: hr.maxSize,
+	}
+	r.pr = pio.NewReader(bufio.NewReader(r.src), r.maxSize).(pio.RawReader)
+	if c.LastSize != 0 {
+		data, err := r.pr.ReadRaw()
+		if err == nil && (protowire.SizeBytes(len(data)) != int(c.LastSize) || recordSum(0, data) != c.LastCrc) {
+			err = errors.New("different record")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w at offset %d: %v", ErrCursorMismatch, start, err)
+		}
+		r.last = data
+	} else if c.GroupLeft != 0 {
+		return nil, errors.New("invalid pquads cursor: no subject group record")
+	}
+	r.n = int(c.Ordinal)
+	r.s, r.p, r.o = c.Subject.ToNative(), c.Predicate.ToNative(), c.Object.ToNative()
+	if len(c.Dict) != 0 {
+		r.dict = make([]quad.Value, len(c.Dict))
+		for i, v := range c.Dict {
+			r.dict[i] = v.ToNative()
+		}
+	}
+	if r.opts.LangDictionary {
+		r.langs = append(hr.langs, c.LangTags...)
+	}
+	if ts := c.LastTime.GetTime(); ts != nil && r.opts.TimeDeltas {
+		r.lastTime, r.hasTime = timestampTime(ts), true
+	}
+	if err := r.resumeGroup(r.last, int(c.GroupLeft)); err != nil {
+		return nil, err
+	}
+	r.srcNames, r.srcName = c.Sources, c.Source
+	r.hasCp, r.cp = c.HasCheckpoint, int(c.Checkpoint)
+	if r.crc {
+		r.sum, r.cpSum, r.lastCp = c.Sum, c.CpSum, c.LastCheckpoint
+	}
+	return r, nil
+}
+
+// resumeGroup restores the last n quads of a subject group record.
+// The group subject is restored with the rest of the cursor state.
+func (r *Reader) resumeGroup(data []byte, n int) error {
+	if n == 0 {
+		return nil
+	}
+	invalid := errors.New("invalid pquads cursor: no subject group to resume")
+	if r.opts.Strict {
+		pq := &StrictQuad{}
+		if err := pq.UnmarshalVT(data); err != nil || len(pq.Group) < n {
+			return invalid
+		} else if err = r.decodeValues(pq); err != nil {
+			return err
+		}
+		r.sgroup = pq.Group[len(pq.Group)-n:]
+	} else {
+		pq := &WireQuad{}
+		if err := pq.UnmarshalVT(data); err != nil || len(pq.Group) < n {
+			return invalid
+		} else if err = r.decodeValues(pq); err != nil {
+			return err
+		}
+		r.wgroup = pq.Group[len(pq.Group)-n:]
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+// writeCursorFile writes quads in subject groups of 10 if they are enabled, switching the source every 25 quads.
+func writeCursorFile(t testing.TB, opts *pquads.Options, quads []quad.Quad) []byte {
+	ctx := context.Background()
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, opts)
+	for i := 0; i < len(quads); i += 10 {
+		if opts.Sources && i%25 < 10 {
+			if err := w.SetSource(fmt.Sprintf("src%d", i/25)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		batch := quads[i:]
+		if len(batch) > 10 {
+			batch = batch[:10]
+		}
+		var err error
+		if opts.SubjectGroups {
+			err = w.WriteSubjectGroup(ctx, batch[0].Subject, batch)
+		} else {
+			_, err = w.WriteQuads(ctx, batch)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCursorResume(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	for _, opts := range []pquads.Options{
+		{},
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{Dictionary: true, Strict: true},
+		{SubjectGroups: true},
+		{SubjectGroups: true, Dictionary: true, Strict: true},
+		{Sources: true},
+		{RollingCRC: true, BlockQuads: 7},
+		{QuadHashes: true, Changelog: true},
+	} {
+		data := writeCursorFile(t, &opts, in)
+		for _, k := range []int{0, 1, 13, 35, 99, 100} {
+			r := pquads.NewReader(bytes.NewReader(data), 0)
+			var out []quad.Quad
+			for len(out) < k {
+				q, err := r.ReadQuad(ctx)
+				if err != nil {
+					t.Fatalf("%+v: %v", opts, err)
+				}
+				out = append(out, q)
+			}
+			cur, err := r.Cursor()
+			if err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			}
+			r.Close()
+			r, err = pquads.ResumeReader(bytes.NewReader(data), cur)
+			if err != nil {
+				t.Fatalf("%+v, %d: %v", opts, k, err)
+			}
+			for {
+				q, err := r.ReadQuad(ctx)
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("%+v, %d: %v", opts, k, err)
+				}
+				out = append(out, q)
+			}
+			if !reflect.DeepEqual(out, in) {
+				t.Fatalf("%+v, %d: wrong quads after resuming", opts, k)
+			}
+			if opts.Sources && k > 0 && k < 100 {
+				fr := pquads.NewReader(bytes.NewReader(data), 0)
+				for i := 0; i <= k; i++ {
+					fr.ReadQuad(ctx)
+				}
+				r, _ = pquads.ResumeReader(bytes.NewReader(data), cur)
+				r.ReadQuad(ctx)
+				if r.SourceName() != fr.SourceName() {
+					t.Fatalf("%d: wrong source after resuming: %q vs %q", k, r.SourceName(), fr.SourceName())
+				}
+			}
+		}
+	}
+}
+
+func TestCursorAppend(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(20)
+	// without closing the writer, the stream can be appended to
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{Dictionary: true})
+	if _, err := w.WriteQuads(ctx, in[:10]); err != nil {
+		t.Fatal(err)
+	}
+	r := pquads.NewReader(bytes.NewReader(buf.Bytes()), 0)
+	got, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := r.Cursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.WriteQuads(ctx, in[10:]); err != nil {
+		t.Fatal(err)
+	}
+	r, err = pquads.ResumeReader(bytes.NewReader(buf.Bytes()), cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got = append(got, rest...); !reflect.DeepEqual(got, in) {
+		t.Fatalf("wrong quads after resuming:\n%v", got)
+	}
+}
+
+func TestCursorMismatch(t *testing.T) {
+	ctx := context.Background()
+	data := writeQuads(t, nil, makeQuads(20))
+	r := pquads.NewReader(bytes.NewReader(data), 0)
+	for i := 0; i < 5; i++ {
+		if _, err := r.ReadQuad(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cur, err := r.Cursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := makeQuads(20)
+	other[4].Object = quad.String("other")
+	for _, data := range [][]byte{
+		writeQuads(t, nil, other),
+		writeQuads(t, &pquads.Options{Full: true}, makeQuads(20)),
+		writeQuads(t, nil, makeQuads(3)),
+	} {
+		if _, err = pquads.ResumeReader(bytes.NewReader(data), cur); !errors.Is(err, pquads.ErrCursorMismatch) {
+			t.Fatalf("expected a mismatch, got: %v", err)
+		}
+	}
+	if _, err = pquads.ResumeReader(bytes.NewReader(data), cur[:len(cur)-1]); err == nil {
+		t.Fatal("expected an error for a truncated cursor")
+	}
+}
+
+func TestCursorCompressed(t *testing.T) {
+	data := writeQuads(t, &pquads.Options{Compress: true}, makeQuads(20))
+	r := pquads.NewReader(bytes.NewReader(data), 0)
+	if _, err := r.ReadQuad(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Cursor(); err == nil {
+		t.Fatal("expected an error for a compressed stream")
+	}
+}
@@ -0,0 +1,58 @@
+package pquads
+
+import (
+	"strings"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/voc/xsd"
+)
+
+// DefaultCanonicalDatatypes is a set of XML Schema datatypes that can be passed to Options.CanonicalDatatypes.
+//
+// It contains the string, boolean, numeric (decimal, integer and all its derived types, float, double),
+// date and time (dateTime, date, time, duration), binary (base64Binary, hexBinary) and anyURI datatypes.
+var DefaultCanonicalDatatypes = []quad.IRI{
+	xsd.String, xsd.Boolean,
+	xsd.Decimal, xsd.Integer, xsd.Long, xsd.Int, xsd.Short, xsd.Byte,
+	xsd.NonPositiveInteger, xsd.NegativeInteger, xsd.NonNegativeInteger, xsd.PositiveInteger,
+	xsd.UnsignedLong, xsd.UnsignedInt, xsd.UnsignedShort, xsd.UnsignedByte,
+	xsd.Float, xsd.Double,
+	xsd.DateTime, xsd.Date, xsd.Prefix + "time", xsd.Prefix + "duration",
+	xsd.Prefix + "base64Binary", xsd.Prefix + "hexBinary", xsd.Prefix + "anyURI",
+}
+
+// datatypeKey returns a key that is the same for all variants of a datatype IRI accepted by canonicalization.
+func datatypeKey(iri quad.IRI) string {
+	s := strings.ToLower(string(iri.Full()))
+	if t := strings.TrimPrefix(s, "https://"); t != s {
+		return t
+	}
+	return strings.TrimPrefix(s, "http://")
+}
+
+// newDatatypeMap maps keys of variants of given datatypes to their canonical IRIs.
+func newDatatypeMap(types []quad.IRI) map[string]quad.IRI {
+	if len(types) == 0 {
+		return nil
+	}
+	m := make(map[string]quad.IRI, len(types))
+	for _, t := range types {
+		t = t.Full()
+		m[datatypeKey(t)] = t
+	}
+	return m
+}
+
+// canonicalDatatype replaces the datatype of a typed string with its canonical IRI, if it's in the map.
+// Any other value is returned unchanged.
+func canonicalDatatype(m map[string]quad.IRI, v quad.Value) quad.Value {
+	ts, ok := v.(quad.TypedString)
+	if !ok {
+		return v
+	}
+	if t, ok := m[datatypeKey(ts.Type)]; ok && t != ts.Type {
+		ts.Type = t
+		return ts
+	}
+	return v
+}
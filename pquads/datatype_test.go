@@ -0,0 +1,83 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestCanonicalDatatypes(t *testing.T) {
+	const (
+		intType  = quad.IRI("http://www.w3.org/2001/XMLSchema#int")
+		boolType = quad.IRI("http://www.w3.org/2001/XMLSchema#boolean")
+	)
+	in := []quad.Quad{
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+	}
+	in[0].Object = quad.TypedString{Value: "1", Type: "https://www.w3.org/2001/XMLSchema#int"}
+	in[1].Object = quad.TypedString{Value: "2", Type: "HTTP://WWW.W3.ORG/2001/XMLSchema#INT"}
+	in[2].Object = quad.TypedString{Value: "3", Type: "xsd:int"}
+	in[3].Object = quad.TypedString{Value: "true", Type: "https://www.w3.org/2001/XMLSchema#Boolean"}
+	in[4].Object = quad.TypedString{Value: "x", Type: "https://example.org/XMLSchema#int"}
+
+	typ := func(i int) quad.IRI { return in[i].Object.(quad.TypedString).Type }
+
+	ctx := context.Background()
+	for _, c := range []struct {
+		name  string
+		opts  pquads.Options
+		types []quad.IRI
+	}{
+		{name: "off", types: []quad.IRI{typ(0), typ(1), typ(2), typ(3), typ(4)}},
+		{name: "default", opts: pquads.Options{CanonicalDatatypes: pquads.DefaultCanonicalDatatypes},
+			types: []quad.IRI{intType, intType, intType, boolType, typ(4)}},
+		{name: "custom", opts: pquads.Options{CanonicalDatatypes: []quad.IRI{"xsd:boolean"}, Strict: true},
+			types: []quad.IRI{typ(0), typ(1), typ(2), boolType, typ(4)}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			w := pquads.NewWriter(buf, &c.opts)
+			if _, err := w.WriteQuads(ctx, in); err != nil {
+				t.Fatal(err)
+			} else if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			quads, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var types []quad.IRI
+			for _, q := range quads {
+				types = append(types, q.Object.(quad.TypedString).Type)
+			}
+			if !reflect.DeepEqual(c.types, types) {
+				t.Fatalf("unexpected datatypes:\n%q\n%q", c.types, types)
+			}
+		})
+	}
+	// booleans are compacted after canonicalization
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{CanonicalDatatypes: pquads.DefaultCanonicalDatatypes, CompactBooleans: true})
+	if err := w.WriteQuad(ctx, in[3]); err != nil {
+		t.Fatal(err)
+	} else if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("boolean")) {
+		t.Fatal("expected a compacted boolean")
+	}
+	quads, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := (quad.TypedString{Value: "true", Type: boolType}); len(quads) != 1 || quads[0].Object != exp {
+		t.Fatalf("expected a canonical boolean, got %v", quads)
+	}
+}
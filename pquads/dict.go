@@ -0,0 +1,151 @@
+package pquads
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"google.golang.org/protobuf/proto"
+)
+
+// sharedKey returns the key of a value in a shared dictionary. Values that are read back as the same value
+// must have the same key, since the dictionary is rebuilt from values read from the primary file: times are stored
+// as an instant, without the location.
+func sharedKey(v quad.Value) quad.Value {
+	if t, ok := v.(quad.Time); ok {
+		return quad.Time(time.Time(t).UTC())
+	}
+	return v
+}
+
+// dictKey returns the key of a value in the dictionary of the writer.
+func (w *Writer) dictKey(v quad.Value) quad.Value {
+	if w.shared {
+		return sharedKey(v)
+	}
+	return v
+}
+
+// dictValue encodes a value, replacing it with a reference if it's already in the dictionary.
+// New values are added to the dictionary, unless it's a shared one.
+func (w *Writer) dictValue(v quad.Value) *Value {
+	if v == nil {
+		return nil
+	}
+	if id, ok := w.dict[w.dictKey(v)]; ok {
+		return &Value{Value: &Value_Ref{Ref: id}}
+	}
+	if !w.shared {
+		w.dict[v] = uint64(len(w.dict))
+	}
+	return MakeValue(v)
+}
+
+// dictRef is the same as dictValue, but for StrictQuad references.
+func (w *Writer) dictRef(v quad.Value) (*StrictQuad_Ref, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if id, ok := w.dict[w.dictKey(v)]; ok {
+		return &StrictQuad_Ref{Value: &StrictQuad_Ref_Ref{Ref: id}}, nil
+	}
+	ref, err := makeRef(v)
+	if err != nil {
+		return nil, err
+	}
+	if !w.shared {
+		w.dict[v] = uint64(len(w.dict))
+	}
+	return ref, nil
+}
+
+// makeDictQuad converts a quad to the wire format using the dictionary.
+// Values are added to the dictionary in the order of directions.
+func (w *Writer) makeDictQuad(q quad.Quad) (proto.Message, error) {
+	if !w.opts.Strict {
+		m := &WireQuad{}
+		m.Subject = w.dictValue(q.Subject)
+		m.Predicate = w.dictValue(q.Predicate)
+		m.Object = w.dictValue(q.Object)
+		m.Label = w.dictValue(q.Label)
+		return m, nil
+	}
+	m := &StrictQuad{}
+	var err error
+	if m.Subject, err = w.dictRef(q.Subject); err != nil {
+		return nil, err
+	}
+	if m.Predicate, err = w.dictRef(q.Predicate); err != nil {
+		return nil, err
+	}
+	m.Object = w.dictValue(q.Object)
+	if m.Label, err = w.dictRef(q.Label); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *Reader) dictLookup(id uint64) (quad.Value, error) {
+	if id >= uint64(len(r.dict)) {
+		return nil, fmt.Errorf("invalid dictionary reference: %d", id)
+	}
+	return r.dict[id], nil
+}
+
+// dictValue decodes a value, resolving dictionary references and adding new values to the dictionary.
+func (r *Reader) dictValue(v *Value) (quad.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if ref, ok := v.Value.(*Value_Ref); ok {
+		return r.dictLookup(ref.Ref)
+	}
+	qv := r.value(v)
+	if !r.shared {
+		r.dict = append(r.dict, qv)
+	}
+	return qv, nil
+}
+
+// dictRef is the same as dictValue, but for StrictQuad references.
+func (r *Reader) dictRef(v *StrictQuad_Ref) (quad.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if ref, ok := v.Value.(*StrictQuad_Ref_Ref); ok {
+		return r.dictLookup(ref.Ref)
+	}
+	qv := r.ref(v)
+	if !r.shared {
+		r.dict = append(r.dict, qv)
+	}
+	return qv, nil
+}
+
+func (r *Reader) dictWireQuad(m *WireQuad) (q quad.Quad, err error) {
+	if q.Subject, err = r.dictValue(m.Subject); err != nil {
+		return
+	}
+	if q.Predicate, err = r.dictValue(m.Predicate); err != nil {
+		return
+	}
+	if q.Object, err = r.dictValue(m.Object); err != nil {
+		return
+	}
+	q.Label, err = r.dictValue(m.Label)
+	return
+}
+
+func (r *Reader) dictStrictQuad(m *StrictQuad) (q quad.Quad, err error) {
+	if q.Subject, err = r.dictRef(m.Subject); err != nil {
+		return
+	}
+	if q.Predicate, err = r.dictRef(m.Predicate); err != nil {
+		return
+	}
+	if q.Object, err = r.dictValue(m.Object); err != nil {
+		return
+	}
+	q.Label, err = r.dictRef(m.Label)
+	return
+}
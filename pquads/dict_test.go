@@ -0,0 +1,95 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestDictionaryInline(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	in[10].Object = in[10].Subject // a definition and a reference in the same quad
+	for _, opts := range []pquads.Options{
+		{Dictionary: true},
+		{Dictionary: true, Strict: true},
+		{Dictionary: true, BlockQuads: 30},
+	} {
+		data := writeQuads(t, &opts, in)
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", opts)
+		}
+
+		// walk the records and check that each reference follows the definition
+		rr := pquads.NewRecordReader(bytes.NewReader(data), 0)
+		defs, refs := 0, 0
+		check := func(ref uint64, ok bool) {
+			if !ok {
+				defs++
+				return
+			}
+			refs++
+			if ref >= uint64(defs) {
+				t.Fatalf("%+v: reference %d precedes the definition (%d defined)", opts, ref, defs)
+			}
+		}
+		value := func(v *pquads.Value) {
+			if v == nil {
+				return
+			}
+			r, ok := v.Value.(*pquads.Value_Ref)
+			if ok {
+				check(r.Ref, true)
+			} else {
+				check(0, false)
+			}
+		}
+		ref := func(v *pquads.StrictQuad_Ref) {
+			if v == nil {
+				return
+			}
+			r, ok := v.Value.(*pquads.StrictQuad_Ref_Ref)
+			if ok {
+				check(r.Ref, true)
+			} else {
+				check(0, false)
+			}
+		}
+		for {
+			rec, err := rr.ReadRecord(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case rec.Type == pquads.RecordCheckpoint:
+				defs = 0
+			case rec.Type != pquads.RecordQuad:
+			case rec.Strict != nil:
+				m := rec.Strict
+				ref(m.Subject)
+				ref(m.Predicate)
+				value(m.Object)
+				ref(m.Label)
+			default:
+				m := rec.Wire
+				value(m.Subject)
+				value(m.Predicate)
+				value(m.Object)
+				value(m.Label)
+			}
+		}
+		if refs == 0 {
+			t.Fatalf("%+v: no references", opts)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package pquads
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cayleygraph/quad"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var _ quad.ReadCloser = (*DropPredicateReader)(nil)
+
+// DropPredicateReader is a quad reader that skips quads with given predicates.
+type DropPredicateReader struct {
+	r    *Reader
+	drop map[quad.Value]bool
+
+	last    []byte     // encoded predicate of the last quad
+	pred    quad.Value // decoded predicate of the last quad
+	dropped bool       // the last predicate is dropped
+}
+
+// NewDropPredicateReader wraps a reader to skip all quads with a predicate from the drop set.
+// Like other wrapping readers, it can itself be wrapped by anything that accepts a quad.Reader.
+//
+// Quads are projected on the predicate first: only the predicate field of each record is decoded, and only
+// when it differs from the predicate of the previous record. Dropped quads are not decoded any further, except
+// for subjects and objects of compacted files that are carried over to the next quads, thus files written with
+// the Full option are filtered the fastest. Files with a dictionary (including a language tag dictionary)
+// or subject groups are decoded completely.
+// Hashes of dropped quads are not verified (see Reader.VerifyHashes), and deletes of dropped quads are skipped
+// without returning ErrTombstone.
+func NewDropPredicateReader(r *Reader, drop map[quad.Value]bool) *DropPredicateReader {
+	return &DropPredicateReader{r: r, drop: drop}
+}
+
+func (d *DropPredicateReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	r := d.r
+	if r.opts.valueState() || r.opts.SubjectGroups {
+		for {
+			q, err := r.ReadQuad(ctx)
+			if err != nil || !d.drop[q.Predicate] {
+				return q, err
+			}
+		}
+	}
+	for {
+		if r.err != nil {
+			return quad.Quad{}, r.err
+		}
+		var data []byte
+		if data, r.err = r.readRaw(); r.err != nil {
+			return quad.Quad{}, r.err
+		}
+		if r.skipNonQuad(data) {
+			if hasField(data, checkpointField) {
+				// checkpoints reset the predicate, other records don't affect compaction
+				d.last, d.pred, d.dropped = d.last[:0], nil, false
+			}
+			continue
+		}
+		p, ok := fieldBytes(data, 2)
+		if ok && (len(d.last) == 0 || !bytes.Equal(p, d.last)) {
+			if d.pred, r.err = r.decodeField(p, 2); r.err != nil {
+				return quad.Quad{}, r.err
+			}
+			d.last = append(d.last[:0], p...)
+			d.dropped = d.drop[d.pred]
+		}
+		if !d.dropped {
+			r.next = data
+			return r.ReadQuad(ctx)
+		}
+		if r.err = r.dropQuad(data, 2, d.pred); r.err != nil {
+			return quad.Quad{}, r.err
+		}
+	}
+}
+
+func (d *DropPredicateReader) Close() error {
+	return d.r.Close()
+}
+
+// decodeField decodes a value field of a quad record.
+func (r *Reader) decodeField(data []byte, num protowire.Number) (quad.Value, error) {
+	if r.opts.Strict && num != 3 {
+		var ref StrictQuad_Ref
+		if err := ref.UnmarshalVT(data); err != nil {
+			return nil, err
+		}
+		return r.ref(&ref), nil
+	}
+	var v Value
+	if err := v.UnmarshalVT(data); err != nil {
+		return nil, err
+	} else if isTimeDelta(&v) {
+		// files with time deltas are decoded completely
+		return nil, fmt.Errorf("unexpected time delta at quad %d", r.n)
+	} else if err := checkCodec(&v); err != nil {
+		return nil, fmt.Errorf("quad %d: %w", r.n, err)
+	}
+	return r.value(&v), nil
+}
+
+// dropQuad skips a quad record, decoding only values that are carried over to the next quads.
+// The value of the field num is already known to be val.
+func (r *Reader) dropQuad(data []byte, num protowire.Number, val quad.Value) error {
+	if !r.opts.Full {
+		for _, v := range []struct {
+			num protowire.Number
+			dst *quad.Value
+		}{{1, &r.s}, {2, &r.p}, {3, &r.o}} {
+			if v.num == num {
+				*v.dst = val
+				continue
+			}
+			b, ok := fieldBytes(data, v.num)
+			if !ok {
+				continue
+			}
+			dv, err := r.decodeField(b, v.num)
+			if err != nil {
+				return err
+			}
+			*v.dst = dv
+		}
+	}
+	r.lastCp = false
+	r.n++
+	return nil
+}
@@ -0,0 +1,110 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+// writeSourceSwitchFile writes quads with a compacted subject and predicate, with the source changed between them.
+func writeSourceSwitchFile(t testing.TB) ([]byte, []quad.Quad) {
+	ctx := context.Background()
+	quads := []quad.Quad{
+		quad.MakeIRI("s", "bad", "o1", ""),
+		quad.MakeIRI("s", "bad", "o2", ""),
+		quad.MakeIRI("s", "good", "o3", ""),
+	}
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{Sources: true})
+	for i, q := range quads {
+		if i < 2 {
+			if err := w.SetSource(string(rune('a' + i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.WriteQuad(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), quads
+}
+
+func TestDropPredicateReader(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100) // predicates p0, p1 and p2 in turn
+	// a few quads with a repeated dropped predicate, followed by compacted quads
+	for i := 0; i < 3; i++ {
+		in = append(in, quad.MakeIRI("x", "p1", "o", ""))
+	}
+	in = append(in, quad.MakeIRI("x", "p2", "o", ""), quad.MakeIRI("x", "p2", "o2", ""))
+	drop := map[quad.Value]bool{quad.IRI("p1"): true, quad.IRI("p3"): true}
+	var exp []quad.Quad
+	for _, q := range in {
+		if !drop[q.Predicate] {
+			exp = append(exp, q)
+		}
+	}
+	for _, opts := range []pquads.Options{
+		{},
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{BlockQuads: 7},
+		{Full: true, BlockQuads: 7, Strict: true},
+		{QuadHashes: true},
+	} {
+		data := writeQuads(t, &opts, in)
+		r := pquads.NewDropPredicateReader(pquads.NewReader(bytes.NewReader(data), 0), drop)
+		out, err := quad.ReadAll(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(exp, out) {
+			t.Fatalf("%+v: unexpected quads:\n%v\n%v", opts, exp, out)
+		}
+	}
+
+	// sources are tracked for dropped quads
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{Sources: true})
+	for i, q := range in[:6] {
+		if err := w.SetSource(string(rune('a' + i))); err != nil {
+			t.Fatal(err)
+		} else if err = w.WriteQuad(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	qr := pquads.NewReader(buf, 0)
+	r := pquads.NewDropPredicateReader(qr, drop)
+	var srcs []string
+	for range exp[:4] {
+		if _, err := r.ReadQuad(ctx); err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, qr.SourceName())
+	}
+	if exp := []string{"a", "c", "d", "f"}; !reflect.DeepEqual(exp, srcs) {
+		t.Fatalf("unexpected sources: %v", srcs)
+	}
+}
+
+func TestDropPredicateReaderSources(t *testing.T) {
+	// source records don't reset the predicate carried over to the next quad
+	data, in := writeSourceSwitchFile(t)
+	r := pquads.NewDropPredicateReader(pquads.NewReader(bytes.NewReader(data), 0), map[quad.Value]bool{quad.IRI("bad"): true})
+	out, err := quad.ReadAll(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := in[2:]; !reflect.DeepEqual(exp, out) {
+		t.Fatalf("unexpected quads:\n%v", out)
+	}
+}
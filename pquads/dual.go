@@ -0,0 +1,157 @@
+package pquads
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cayleygraph/quad"
+)
+
+var _ quad.WriteCloser = (*DualOrderWriter)(nil)
+
+// DualOrderWriter is a quad writer that sorts all written quads in two orders at once and writes them to two
+// outputs: one in canonical order (see CompareQuads) and a companion ordered by objects (see CompareQuadsByObject).
+// Together, they allow scanning quads of a range of subjects or of a range of objects without a second pass
+// over the source.
+//
+// Quads are sorted the same way as by ExternalSortWriter: they are buffered in memory until the buffer reaches
+// the memory limit, after which it's sorted in both orders and spilled to two temporary files (a run per order).
+// On Close, the runs of each order are merged and written to the respective output, first the subject-ordered one.
+// Buffered values are shared by both orders, thus the second order only costs a few words of memory per quad,
+// but temporary files take twice the space of a single sort. Temporary files are removed on Close, even if
+// an error occurs, thus Close must always be called.
+//
+// Values of the companion refer to a dictionary shared with the subject-ordered output (see Header.shared_dictionary),
+// thus each value is stored in full only once for both files. The companion must be read with NewCompanionReader,
+// which rebuilds the dictionary from the subject-ordered output. The value dictionary is kept in memory until Close,
+// in addition to the memory limit. The subject-ordered output is a complete pquads file written with the given
+// options, readable independently. Options.Dictionary only applies to it.
+type DualOrderWriter struct {
+	s, o  *ExternalSortWriter
+	limit int64
+	size  int64
+	err   error
+}
+
+// NewDualOrderWriter creates a writer that writes quads ordered by subjects to sOut and ordered by objects to oOut,
+// once it's closed. Temporary files are created in tmpDir, or in the default directory for temporary files
+// if it's empty. MemLimit is an approximate limit for the memory used by buffered quads of both orders.
+// DefaultSortMemLimit is used if it's not positive.
+func NewDualOrderWriter(sOut, oOut io.Writer, tmpDir string, memLimit int64, opts *Options) *DualOrderWriter {
+	if memLimit <= 0 {
+		memLimit = DefaultSortMemLimit
+	}
+	w := &DualOrderWriter{
+		s:     NewExternalSortWriter(sOut, tmpDir, memLimit, opts),
+		o:     NewExternalSortWriter(oOut, tmpDir, memLimit, opts),
+		limit: memLimit,
+	}
+	dict := make(map[quad.Value]uint64)
+	w.s.newOut = func(dst io.Writer, opts *Options) *Writer {
+		qw := NewWriter(dst, opts)
+		qw.collect = dict
+		return qw
+	}
+	w.o.newOut = func(dst io.Writer, opts *Options) *Writer {
+		return newWriter(dst, opts, dict)
+	}
+	w.o.cmp = CompareQuadsByObject
+	return w
+}
+
+func (w *DualOrderWriter) WriteQuad(ctx context.Context, q quad.Quad) error {
+	if w.err != nil {
+		return w.err
+	} else if !q.IsValid() {
+		return quad.ErrInvalid
+	}
+	w.s.buf = append(w.s.buf, q)
+	w.o.buf = append(w.o.buf, q)
+	// the second copy only duplicates interface headers
+	w.size += quadMemSize(q) + quadMemSize(quad.Quad{})
+	if w.size >= w.limit {
+		if w.err = w.s.spill(ctx); w.err == nil {
+			w.err = w.o.spill(ctx)
+		}
+		if w.err != nil {
+			w.s.cleanup()
+			w.o.cleanup()
+		}
+		w.size = 0
+	}
+	return w.err
+}
+
+func (w *DualOrderWriter) WriteQuads(ctx context.Context, buf []quad.Quad) (int, error) {
+	for i, q := range buf {
+		if err := w.WriteQuad(ctx, q); err != nil {
+			return i, err
+		}
+	}
+	return len(buf), nil
+}
+
+// Close sorts all quads and writes them to both outputs. It removes all temporary files.
+//
+// The companion is only written if the subject-ordered output was written successfully, since it depends
+// on the dictionary of the latter.
+func (w *DualOrderWriter) Close() error {
+	if w.err != nil {
+		w.s.cleanup()
+		w.o.cleanup()
+		return w.err
+	}
+	w.err = errClosed
+	if err := w.s.Close(); err != nil {
+		w.o.cleanup()
+		return err
+	}
+	return w.o.Close()
+}
+
+// NewCompanionReader creates a reader for a file with values that refer to a dictionary shared with the primary file,
+// like the object-ordered output of DualOrderWriter. The primary file (the subject-ordered output) is read first
+// to rebuild the dictionary, which is kept in memory. See NewReader for the description of maxSize.
+func NewCompanionReader(r, primary io.Reader, maxSize int) *Reader {
+	qr, h := openReader(r, maxSize)
+	if qr.err != nil {
+		return qr
+	} else if !h.SharedDictionary {
+		qr.err = fmt.Errorf("pquads file has no shared dictionary")
+		return qr
+	}
+	dict, err := readSharedDict(primary, maxSize)
+	if err != nil {
+		qr.err = fmt.Errorf("cannot read shared dictionary: %w", err)
+	} else if uint64(len(dict)) != h.SharedValues {
+		qr.err = fmt.Errorf("shared dictionary has %d values, expected %d", len(dict), h.SharedValues)
+	}
+	qr.dict = dict
+	return qr
+}
+
+// readSharedDict collects distinct values of all quads of the primary file, see Header.shared_dictionary.
+func readSharedDict(r io.Reader, maxSize int) ([]quad.Value, error) {
+	pr := NewReader(r, maxSize)
+	defer pr.Close()
+	ctx := context.Background()
+	var dict []quad.Value
+	seen := make(map[quad.Value]struct{})
+	for {
+		q, err := pr.ReadQuad(ctx)
+		if err == io.EOF {
+			return dict, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for _, d := range quad.Directions {
+			if v := q.Get(d); v != nil {
+				if _, ok := seen[v]; !ok {
+					seen[v] = struct{}{}
+					dict = append(dict, v)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestDualOrderWriter(t *testing.T) {
+	ctx := context.Background()
+	in := shuffledQuads(2000)
+	expS := make([]quad.Quad, len(in))
+	copy(expS, in)
+	sort.Sort(quad.ByQuadString(expS))
+	expO := make([]quad.Quad, len(in))
+	copy(expO, in)
+	sort.SliceStable(expO, func(i, j int) bool {
+		return pquads.CompareQuadsByObject(expO[i], expO[j]) < 0
+	})
+	if reflect.DeepEqual(expS, expO) {
+		t.Fatal("orders must differ")
+	}
+
+	for _, c := range []struct {
+		limit int64
+		opts  pquads.Options
+	}{
+		{1 << 30, pquads.Options{Dictionary: true}},
+		{4 << 10, pquads.Options{Dictionary: true}},
+		{4 << 10, pquads.Options{Strict: true, Full: true}},
+		{0, pquads.Options{CompactBooleans: true}},
+	} {
+		dir := t.TempDir()
+		sbuf, obuf := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+		opts := c.opts
+		w := pquads.NewDualOrderWriter(sbuf, obuf, dir, c.limit, &opts)
+		if _, err := w.WriteQuads(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+		if c.limit == 4<<10 {
+			if files, _ := os.ReadDir(dir); len(files) == 0 || len(files)%2 != 0 {
+				t.Fatalf("expected pairs of temporary runs, got %d", len(files))
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Fatalf("temporary files were not removed: %d", len(files))
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(sbuf.Bytes()), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(expS, out) {
+			t.Fatalf("unexpected subject order with %+v", c)
+		}
+		out, err = quad.ReadAll(ctx, pquads.NewCompanionReader(bytes.NewReader(obuf.Bytes()), bytes.NewReader(sbuf.Bytes()), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(expO, out) {
+			t.Fatalf("unexpected object order with %+v", c)
+		}
+
+		// the companion only stores values in full once, thus it's smaller than a standalone file
+		opts.Dictionary = false
+		if plain := writeQuads(t, &opts, expO); obuf.Len() >= len(plain) {
+			t.Fatalf("companion is not smaller than a standalone file: %d vs %d", obuf.Len(), len(plain))
+		}
+	}
+}
+
+func TestCompanionReader(t *testing.T) {
+	ctx := context.Background()
+	in := shuffledQuads(100)
+	sbuf, obuf := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+	w := pquads.NewDualOrderWriter(sbuf, obuf, t.TempDir(), 0, nil)
+	if _, err := w.WriteQuads(ctx, in); err != nil {
+		t.Fatal(err)
+	} else if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pquads.NewReader(bytes.NewReader(obuf.Bytes()), 0).ReadQuad(ctx); err == nil || err == io.EOF {
+		t.Fatalf("expected an error for a companion without the dictionary, got %v", err)
+	}
+	// a different primary file
+	other := writeQuads(t, nil, in[:50])
+	r := pquads.NewCompanionReader(bytes.NewReader(obuf.Bytes()), bytes.NewReader(other), 0)
+	if _, err := r.ReadQuad(ctx); err == nil || err == io.EOF {
+		t.Fatalf("expected an error for a wrong primary file, got %v", err)
+	}
+	// not a companion
+	r = pquads.NewCompanionReader(bytes.NewReader(sbuf.Bytes()), bytes.NewReader(sbuf.Bytes()), 0)
+	if _, err := r.ReadQuad(ctx); err == nil || err == io.EOF {
+		t.Fatalf("expected an error for a file without a shared dictionary, got %v", err)
+	}
+}
+
+func TestDualOrderWriterTimes(t *testing.T) {
+	ctx := context.Background()
+	// the same instants in different locations are read back as the same values
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	zone := time.FixedZone("UTC+3", 3*3600)
+	var in []quad.Quad
+	for i := 0; i < 20; i++ {
+		ts := t0.Add(time.Duration(i/4) * time.Hour)
+		if i%2 == 1 {
+			ts = ts.In(zone)
+		}
+		in = append(in, quad.Quad{Subject: quad.IRI(fmt.Sprintf("s%d", i%5)), Predicate: quad.IRI("at"), Object: quad.Time(ts)})
+	}
+	for _, opts := range []*pquads.Options{nil, {Strict: true, Full: true}} {
+		sbuf, obuf := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+		w := pquads.NewDualOrderWriter(sbuf, obuf, t.TempDir(), 0, opts)
+		if _, err := w.WriteQuads(ctx, in); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		squads, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(sbuf.Bytes()), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		oquads, err := quad.ReadAll(ctx, pquads.NewCompanionReader(bytes.NewReader(obuf.Bytes()), bytes.NewReader(sbuf.Bytes()), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(quad.ByQuadString(oquads))
+		if !reflect.DeepEqual(squads, oquads) {
+			t.Fatalf("%+v: different quads in outputs:\n%v\n%v", opts, squads, oquads)
+		}
+	}
+}
+
+func TestDualOrderWriterCleanup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	w := pquads.NewDualOrderWriter(bytes.NewBuffer(nil), failWriter{}, dir, 4<<10, nil)
+	if _, err := w.WriteQuads(ctx, shuffledQuads(1000)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); !errors.Is(err, errWrite) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("temporary files were not removed: %d", len(files))
+	}
+}
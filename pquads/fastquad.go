@@ -0,0 +1,180 @@
+package pquads
+
+import (
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cayleygraph/quad"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodeFast decodes a record with decodeQuadFast, if the reader doesn't need the protobuf messages
+// to resolve dictionary references or to pass to the allocator or the value pool.
+func (r *Reader) decodeFast(data []byte) (quad.Quad, bool) {
+	if r.opts.valueState() || r.alloc != nil || r.pool != nil {
+		return quad.Quad{}, false
+	}
+	tm := r.src.tm
+	if tm == nil {
+		return decodeQuadFast(data, r.opts.Strict)
+	}
+	start := time.Now()
+	q, ok := decodeQuadFast(data, r.opts.Strict)
+	tm.Decode.add(time.Since(start))
+	return q, ok
+}
+
+// decodeQuadFast decodes a quad record directly from the wire format, without the intermediate protobuf messages.
+// This saves two allocations per value compared to unmarshaling the record and calling ToNative.
+//
+// It only supports records that have nothing but quad values, each of them an IRI, a blank node, a string,
+// a typed or language string, an integer, a float or a boolean. It reports false for any other record
+// (including invalid ones), which must be decoded as a message instead.
+func decodeQuadFast(data []byte, strict bool) (quad.Quad, bool) {
+	var (
+		q    quad.Quad
+		seen [4]bool
+	)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 || num < 1 || num > 4 || typ != protowire.BytesType || seen[num-1] {
+			return quad.Quad{}, false
+		}
+		seen[num-1] = true
+		data = data[n:]
+		b, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return quad.Quad{}, false
+		}
+		data = data[n:]
+		var (
+			v  quad.Value
+			ok bool
+		)
+		if strict && num != 3 {
+			v, ok = decodeRefFast(b)
+		} else {
+			v, ok = decodeValueFast(b)
+		}
+		if !ok {
+			return quad.Quad{}, false
+		}
+		switch num {
+		case 1:
+			q.Subject = v
+		case 2:
+			q.Predicate = v
+		case 3:
+			q.Object = v
+		case 4:
+			q.Label = v
+		}
+	}
+	return q, true
+}
+
+// decodeValueFast decodes a Value message of one of the types supported by decodeQuadFast.
+func decodeValueFast(data []byte) (quad.Value, bool) {
+	num, typ, n := protowire.ConsumeTag(data)
+	if n < 0 {
+		return nil, false
+	}
+	data = data[n:]
+	var v quad.Value
+	switch {
+	case typ == protowire.BytesType && num >= 2 && num <= 6:
+		b, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, false
+		}
+		data = data[n:]
+		switch num {
+		case 2, 3, 4:
+			if !utf8.Valid(b) {
+				return nil, false
+			}
+			switch num {
+			case 2:
+				v = quad.String(b)
+			case 3:
+				v = quad.IRI(b)
+			case 4:
+				v = quad.BNode(b)
+			}
+		case 5:
+			s, t, ok := decodeStringPair(b)
+			if !ok {
+				return nil, false
+			}
+			v = quad.TypedString{Value: quad.String(s), Type: quad.IRI(t)}
+		case 6:
+			s, l, ok := decodeStringPair(b)
+			if !ok {
+				return nil, false
+			}
+			v = quad.LangString{Value: quad.String(s), Lang: string(l)}
+		}
+	case typ == protowire.VarintType && (num == 7 || num == 9):
+		x, n := protowire.ConsumeVarint(data)
+		if n < 0 {
+			return nil, false
+		}
+		data = data[n:]
+		if num == 7 {
+			v = quad.Int(int64(x))
+		} else {
+			v = quad.Bool(x != 0)
+		}
+	case typ == protowire.Fixed64Type && num == 8:
+		x, n := protowire.ConsumeFixed64(data)
+		if n < 0 {
+			return nil, false
+		}
+		data = data[n:]
+		v = quad.Float(math.Float64frombits(x))
+	default:
+		return nil, false
+	}
+	// a oneof has a single field, anything else is left to the full decoder
+	return v, len(data) == 0
+}
+
+// decodeRefFast decodes a StrictQuad_Ref message with an IRI or a blank node.
+func decodeRefFast(data []byte) (quad.Value, bool) {
+	num, typ, n := protowire.ConsumeTag(data)
+	if n < 0 || typ != protowire.BytesType || (num != 2 && num != 3) {
+		return nil, false
+	}
+	b, m := protowire.ConsumeBytes(data[n:])
+	if m < 0 || n+m != len(data) || !utf8.Valid(b) {
+		return nil, false
+	}
+	if num == 2 {
+		return quad.BNode(b), true
+	}
+	return quad.IRI(b), true
+}
+
+// decodeStringPair decodes a message with two string fields, as Value_TypedString and Value_LangString.
+func decodeStringPair(data []byte) (a, b []byte, ok bool) {
+	var seen [2]bool
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 || (num != 1 && num != 2) || typ != protowire.BytesType || seen[num-1] {
+			return nil, nil, false
+		}
+		seen[num-1] = true
+		s, m := protowire.ConsumeBytes(data[n:])
+		if m < 0 || !utf8.Valid(s) {
+			return nil, nil, false
+		}
+		data = data[n+m:]
+		if num == 1 {
+			a = s
+		} else {
+			b = s
+		}
+	}
+	return a, b, true
+}
@@ -0,0 +1,87 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestQuadFlags(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(30)
+	const inferred = 1 << 0
+	flags := func(i int) uint32 {
+		if i%3 == 0 {
+			return 0
+		}
+		return inferred | uint32(i%5)<<8
+	}
+	for _, opts := range []*pquads.Options{
+		nil,
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true, QuadHashes: true},
+	} {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		for i, q := range quads {
+			if err := w.WriteQuadFlags(ctx, q, flags(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.VerifyHashes(true)
+		for i := 0; ; i++ {
+			q, f, err := r.ReadQuadFlags(ctx)
+			if err == io.EOF {
+				if i != len(quads) {
+					t.Fatalf("%+v: expected %d quads, got %d", opts, len(quads), i)
+				}
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if q != quads[i] {
+				t.Fatalf("%+v: quad %d: %v vs %v", opts, i, q, quads[i])
+			} else if f != flags(i) || r.QuadFlags() != f {
+				t.Fatalf("%+v: flags of quad %d: %x vs %x", opts, i, f, flags(i))
+			}
+		}
+
+		// flags survive rewriting
+		out := bytes.NewBuffer(nil)
+		if _, err := pquads.Rewrite(out, bytes.NewReader(data), 0, &pquads.Options{Full: true}); err != nil {
+			t.Fatal(err)
+		}
+		r = pquads.NewReader(out, 0)
+		for i := range quads {
+			if _, f, err := r.ReadQuadFlags(ctx); err != nil {
+				t.Fatal(err)
+			} else if f != flags(i) {
+				t.Fatalf("%+v: flags of rewritten quad %d: %x vs %x", opts, i, f, flags(i))
+			}
+		}
+	}
+
+	// zero flags cost nothing
+	plain := writeQuads(t, nil, quads)
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, nil)
+	for _, q := range quads {
+		if err := w.WriteQuadFlags(ctx, q, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(buf.Bytes(), plain) {
+		t.Fatal("zero flags changed the encoding")
+	}
+}
@@ -0,0 +1,292 @@
+package pquads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cayleygraph/quad"
+)
+
+// EachSubject reads all remaining quads and calls fn once for each run of consecutive quads sharing the same subject.
+//
+// It's intended for subject-sorted files. If the input is not sorted by subject, fn will be called
+// once for every run of the subject, thus it may be called multiple times for the same subject.
+//
+// The quads slice is reused between calls and must not be retained by fn.
+// The iteration stops at the first error returned by fn, which is returned to the caller.
+func (r *Reader) EachSubject(ctx context.Context, fn func(subj quad.Value, quads []quad.Quad) error) error {
+	var buf []quad.Quad
+	for {
+		q, err := r.ReadQuad(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if len(buf) != 0 && q.Subject != buf[0].Subject {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+			if err = fn(buf[0].Subject, buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+		}
+		buf = append(buf, q)
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	return fn(buf[0].Subject, buf)
+}
+
+// subjectGroups reads runs of quads with the same subject.
+type subjectGroups struct {
+	r    *Reader
+	next quad.Quad // first quad of the next group
+	buf  []quad.Quad
+	subj string // subject of the current group
+	eof  bool
+}
+
+// read reads the next group into buf. It returns false and a nil error at the end of the stream.
+func (g *subjectGroups) read(ctx context.Context) (bool, error) {
+	g.buf = g.buf[:0]
+	if g.next.Subject == nil {
+		if g.eof {
+			return false, nil
+		}
+		q, err := g.r.ReadQuad(ctx)
+		if err == io.EOF {
+			g.eof = true
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		g.next = q
+	}
+	subj := quad.StringOf(g.next.Subject)
+	if len(g.subj) != 0 && subj < g.subj {
+		return false, fmt.Errorf("input is not sorted by subject: %v after %v", subj, g.subj)
+	}
+	g.subj = subj
+	g.buf = append(g.buf, g.next)
+	g.next = quad.Quad{}
+	for {
+		q, err := g.r.ReadQuad(ctx)
+		if err == io.EOF {
+			g.eof = true
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		if q.Subject != g.buf[0].Subject {
+			g.next = q
+			return true, nil
+		}
+		g.buf = append(g.buf, q)
+	}
+}
+
+// JoinOnSubject performs a full outer merge-join of two files on subject. It calls fn once for each subject
+// present in at least one of the files, in order, with all quads of this subject from each file. If the subject
+// is present only in one of the files, quads for the other file will be empty.
+//
+// Both files must be sorted by subject in canonical order (see CompareQuads), which is checked while reading.
+// Only one group of quads with the same subject is kept in memory for each file, and slices are reused
+// between calls, thus fn must not retain them. The join stops at the first error returned by fn.
+func JoinOnSubject(a, b *Reader, fn func(subj quad.Value, aQuads, bQuads []quad.Quad) error) error {
+	ctx := context.Background()
+	ga, gb := &subjectGroups{r: a}, &subjectGroups{r: b}
+	okA, err := ga.read(ctx)
+	if err != nil {
+		return err
+	}
+	okB, err := gb.read(ctx)
+	if err != nil {
+		return err
+	}
+	for okA || okB {
+		c := 0
+		if !okA {
+			c = 1
+		} else if !okB {
+			c = -1
+		} else if ga.subj != gb.subj {
+			c = strings.Compare(ga.subj, gb.subj)
+		}
+		switch {
+		case c < 0:
+			err = fn(ga.buf[0].Subject, ga.buf, nil)
+		case c > 0:
+			err = fn(gb.buf[0].Subject, nil, gb.buf)
+		default:
+			err = fn(ga.buf[0].Subject, ga.buf, gb.buf)
+		}
+		if err != nil {
+			return err
+		}
+		if c <= 0 {
+			if okA, err = ga.read(ctx); err != nil {
+				return err
+			}
+		}
+		if c >= 0 {
+			if okB, err = gb.read(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteSubjectGroup writes quads of a single subject as one subject group record. It requires Options.SubjectGroups.
+// All quads must have the given subject. Readers return quads of the group one by one, as if they were written
+// with WriteQuad, or all at once with Reader.ReadSubjectGroup.
+//
+// The group record contains the subject once, followed by the list of quads without it. Other values
+// are compacted and deduplicated the same way as for separate quads, thus the group only saves the framing
+// and the subject. See Header.subject_groups for the description of the wire layout.
+func (w *Writer) WriteSubjectGroup(ctx context.Context, subj quad.Value, quads []quad.Quad) error {
+	if w.err != nil {
+		return w.err
+	} else if !w.opts.SubjectGroups {
+		return errors.New("subject groups require Options.SubjectGroups")
+	} else if subj == nil {
+		return quad.ErrInvalid
+	} else if len(quads) == 0 {
+		return errors.New("empty subject group")
+	}
+	invalidUTF8 := make([]bool, len(quads))
+	for i, q := range quads {
+		if q.Subject != subj {
+			return fmt.Errorf("subject of quad %d doesn't match the group: %v vs %v", w.n+i, q.Subject, subj)
+		}
+		var err error
+		if invalidUTF8[i], err = w.checkChange(q, OpAdd, nil, w.n+i); err != nil {
+			return err
+		}
+	}
+	if err := w.startRecord(); err != nil {
+		return err
+	}
+	var (
+		wg *WireQuad
+		sg *StrictQuad
+	)
+	for i, q := range quads {
+		m, err := w.encodeChange(q, OpAdd, nil, 0, invalidUTF8[i])
+		if err != nil {
+			return err
+		}
+		// the subject of the first quad is moved to the group record, others are dropped
+		switch m := m.(type) {
+		case *WireQuad:
+			if wg == nil {
+				wg = &WireQuad{Subject: m.Subject}
+			}
+			m.Subject = nil
+			wg.Group = append(wg.Group, m)
+		case *StrictQuad:
+			if sg == nil {
+				sg = &StrictQuad{Subject: m.Subject}
+			}
+			m.Subject = nil
+			sg.Group = append(sg.Group, m)
+		}
+	}
+	if wg != nil {
+		return w.writeRecord(wg, len(quads))
+	}
+	return w.writeRecord(sg, len(quads))
+}
+
+// ReadSubjectGroup reads all quads of the next subject group record, as written by Writer.WriteSubjectGroup.
+// Quads written as separate records are returned as groups of a single quad, thus subsequent calls may return
+// the same subject. If a part of the group was already read with ReadQuad, it returns the rest of the group.
+//
+// Like ReadQuad, it fails with ErrTombstone on deletes.
+func (r *Reader) ReadSubjectGroup(ctx context.Context) (quad.Value, []quad.Quad, error) {
+	q, err := r.ReadQuad(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	quads := []quad.Quad{q}
+	for len(r.wgroup) != 0 || len(r.sgroup) != 0 {
+		if q, err = r.ReadQuad(ctx); err != nil {
+			return nil, nil, err
+		}
+		quads = append(quads, q)
+	}
+	return q.Subject, quads, nil
+}
+
+// invalidGroupEntry returns an error for a record in a subject group that is not a quad without a subject.
+func (r *Reader) invalidGroupEntry(i int) error {
+	return fmt.Errorf("invalid record %d in subject group of quad %d", i, r.n)
+}
+
+// setGroupSubject sets the subject for quads of a group.
+func (r *Reader) setGroupSubject(s quad.Value) error {
+	if !r.opts.SubjectGroups {
+		return fmt.Errorf("unexpected subject group at quad %d", r.n)
+	} else if s != nil {
+		r.s = s
+	} else if r.s == nil {
+		return fmt.Errorf("no subject for subject group at quad %d", r.n)
+	}
+	return nil
+}
+
+func (r *Reader) startWireGroup(pq *WireQuad) error {
+	for i, e := range pq.Group {
+		if e.Subject != nil || e.Checkpoint != nil || e.Index != nil || e.Footer != nil || len(e.Group) != 0 ||
+			e.Source != nil {
+			return r.invalidGroupEntry(i)
+		}
+	}
+	var (
+		s   quad.Value
+		err error
+	)
+	if r.opts.Dictionary {
+		if s, err = r.dictValue(pq.Subject); err != nil {
+			return err
+		}
+	} else {
+		s = r.value(pq.Subject)
+	}
+	if err = r.setGroupSubject(s); err != nil {
+		return err
+	}
+	r.wgroup = pq.Group
+	return nil
+}
+
+func (r *Reader) startStrictGroup(pq *StrictQuad) error {
+	for i, e := range pq.Group {
+		if e.Subject != nil || e.Checkpoint != nil || e.Index != nil || e.Footer != nil || len(e.Group) != 0 ||
+			e.Source != nil {
+			return r.invalidGroupEntry(i)
+		}
+	}
+	var (
+		s   quad.Value
+		err error
+	)
+	if r.opts.Dictionary {
+		if s, err = r.dictRef(pq.Subject); err != nil {
+			return err
+		}
+	} else {
+		s = r.ref(pq.Subject)
+	}
+	if err = r.setGroupSubject(s); err != nil {
+		return err
+	}
+	r.sgroup = pq.Group
+	return nil
+}
@@ -0,0 +1,306 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestEachSubject(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(25)
+	// not sorted: the first subject appears again
+	in = append(in, makeQuads(2)...)
+	r := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0)
+
+	var (
+		subjs  []quad.Value
+		counts []int
+		out    []quad.Quad
+	)
+	err := r.EachSubject(ctx, func(subj quad.Value, quads []quad.Quad) error {
+		for _, q := range quads {
+			if q.Subject != subj {
+				t.Fatalf("unexpected subject in group %v: %v", subj, q)
+			}
+		}
+		subjs = append(subjs, subj)
+		counts = append(counts, len(quads))
+		out = append(out, quads...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expSubjs := []quad.Value{quad.IRI("s0"), quad.IRI("s1"), quad.IRI("s2"), quad.IRI("s0")}
+	if !reflect.DeepEqual(expSubjs, subjs) {
+		t.Fatalf("unexpected subjects: %v", subjs)
+	}
+	if !reflect.DeepEqual([]int{10, 10, 5, 2}, counts) {
+		t.Fatalf("unexpected group sizes: %v", counts)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatal("corrupted quads")
+	}
+}
+
+func TestEachSubjectError(t *testing.T) {
+	ctx := context.Background()
+	r := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, makeQuads(25))), 0)
+	errStop := errors.New("stop")
+	calls := 0
+	err := r.EachSubject(ctx, func(subj quad.Value, quads []quad.Quad) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Fatalf("unexpected result: %v, %d calls", err, calls)
+	}
+}
+
+type person struct {
+	Name    string
+	Age     int
+	Friends []quad.IRI
+	Homes   []quad.Value
+	Active  bool
+}
+
+func TestStructScanner(t *testing.T) {
+	ctx := context.Background()
+	in := []quad.Quad{
+		quad.Make(quad.IRI("alice"), quad.IRI("name"), "Alice", nil),
+		quad.Make(quad.IRI("alice"), quad.IRI("age"), 30, nil),
+		quad.Make(quad.IRI("alice"), quad.IRI("knows"), quad.IRI("bob"), nil),
+		quad.Make(quad.IRI("alice"), quad.IRI("knows"), quad.IRI("carol"), nil),
+		quad.Make(quad.IRI("alice"), quad.IRI("home"), quad.IRI("paris"), nil),
+		quad.Make(quad.IRI("alice"), quad.IRI("unknown"), "x", nil),
+		quad.Make(quad.IRI("bob"), quad.IRI("name"), quad.LangString{Value: "Bob", Lang: "en"}, nil),
+		quad.Make(quad.IRI("bob"), quad.IRI("active"), quad.TypedString{Value: "true", Type: "xsd:boolean"}, nil),
+		quad.Make(quad.IRI("carol"), quad.IRI("name"), "Carol", nil),
+		quad.Make(quad.IRI("carol"), quad.IRI("name"), "Karol", nil),
+	}
+	r := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0)
+	s := pquads.NewStructScanner(r, map[quad.IRI]string{
+		"name": "Name", "age": "Age", "knows": "Friends", "home": "Homes", "active": "Active",
+	})
+	var p person
+	subj, err := s.Scan(ctx, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := person{Name: "Alice", Age: 30, Friends: []quad.IRI{"bob", "carol"}, Homes: []quad.Value{quad.IRI("paris")}}
+	if subj != quad.IRI("alice") || !reflect.DeepEqual(exp, p) {
+		t.Fatalf("unexpected result: %v, %#v", subj, p)
+	}
+	subj, err = s.Scan(ctx, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = person{Name: "Bob", Active: true}
+	if subj != quad.IRI("bob") || !reflect.DeepEqual(exp, p) {
+		t.Fatalf("unexpected result: %v, %#v", subj, p)
+	}
+	if _, err = s.Scan(ctx, &p); err == nil {
+		t.Fatal("expected an error for multiple values")
+	}
+	if _, err = s.Scan(ctx, p); err == nil {
+		t.Fatal("expected an error for non-pointer")
+	}
+}
+
+func TestStructScannerEOF(t *testing.T) {
+	ctx := context.Background()
+	r := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, makeQuads(15))), 0)
+	s := pquads.NewStructScanner(r, nil)
+	var p person
+	for _, exp := range []quad.Value{quad.IRI("s0"), quad.IRI("s1")} {
+		if subj, err := s.Scan(ctx, &p); err != nil || subj != exp {
+			t.Fatalf("unexpected result: %v, %v", subj, err)
+		}
+	}
+	if _, err := s.Scan(ctx, &p); err != io.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+}
+
+func TestStructScannerConversions(t *testing.T) {
+	ctx := context.Background()
+	type numbers struct {
+		Int   int
+		Small int8
+		Uint  uint
+		F32   float32
+		F64   float64
+	}
+	for _, c := range []struct {
+		field string
+		val   quad.Value
+		exp   numbers
+	}{
+		{field: "Int", val: quad.Float(2), exp: numbers{Int: 2}},
+		{field: "Int", val: quad.Float(1.5)},
+		{field: "Int", val: quad.Float(1e30)},
+		{field: "Small", val: quad.Int(-128), exp: numbers{Small: -128}},
+		{field: "Small", val: quad.Int(300)},
+		{field: "Uint", val: quad.Int(7), exp: numbers{Uint: 7}},
+		{field: "Uint", val: quad.Int(-1)},
+		{field: "Uint", val: quad.Float(-0.5)},
+		{field: "F32", val: quad.Float(0.1), exp: numbers{F32: 0.1}},
+		{field: "F32", val: quad.Float(1e300)},
+		{field: "F64", val: quad.Int(1 << 53), exp: numbers{F64: 1 << 53}},
+		{field: "F64", val: quad.Int(1<<53 + 1)},
+	} {
+		in := []quad.Quad{quad.Make(quad.IRI("s"), quad.IRI("p"), c.val, nil)}
+		r := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0)
+		s := pquads.NewStructScanner(r, map[quad.IRI]string{"p": c.field})
+		var got numbers
+		_, err := s.Scan(ctx, &got)
+		if c.exp == (numbers{}) {
+			if !errors.Is(err, pquads.ErrLossyConversion) {
+				t.Fatalf("expected an error for %v into %s, got %v (%+v)", c.val, c.field, err, got)
+			}
+		} else if err != nil {
+			t.Fatal(err)
+		} else if got != c.exp {
+			t.Fatalf("unexpected result for %v into %s: %+v", c.val, c.field, got)
+		}
+	}
+}
+
+func TestJoinOnSubject(t *testing.T) {
+	qa := makeQuads(50)      // s0 - s4
+	qb := makeQuads(80)[30:] // s3 - s7
+	open := func(quads []quad.Quad) *pquads.Reader {
+		return pquads.NewReader(bytes.NewReader(writeQuads(t, nil, quads)), 0)
+	}
+	type group struct {
+		subj quad.Value
+		a, b int
+	}
+	var got []group
+	err := pquads.JoinOnSubject(open(qa), open(qb), func(subj quad.Value, a, b []quad.Quad) error {
+		for _, q := range append(append([]quad.Quad{}, a...), b...) {
+			if q.Subject != subj {
+				t.Fatalf("unexpected subject in group %v: %v", subj, q)
+			}
+		}
+		got = append(got, group{subj: subj, a: len(a), b: len(b)})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []group{
+		{quad.IRI("s0"), 10, 0},
+		{quad.IRI("s1"), 10, 0},
+		{quad.IRI("s2"), 10, 0},
+		{quad.IRI("s3"), 10, 10},
+		{quad.IRI("s4"), 10, 10},
+		{quad.IRI("s5"), 0, 10},
+		{quad.IRI("s6"), 0, 10},
+		{quad.IRI("s7"), 0, 10},
+	}
+	if !reflect.DeepEqual(exp, got) {
+		t.Fatalf("unexpected groups:\n%v\n%v", exp, got)
+	}
+
+	unsorted := append(makeQuads(20)[10:], makeQuads(10)...)
+	err = pquads.JoinOnSubject(open(qa), open(unsorted), func(subj quad.Value, a, b []quad.Quad) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error for unsorted input")
+	}
+	errStop := errors.New("stop")
+	err = pquads.JoinOnSubject(open(qa), open(qb), func(subj quad.Value, a, b []quad.Quad) error {
+		return errStop
+	})
+	if err != errStop {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSubjectGroups(t *testing.T) {
+	ctx := context.Background()
+	all := makeQuads(35)
+	for _, opts := range []*pquads.Options{
+		{},
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{Strict: true, Dictionary: true, Full: true},
+		{QuadHashes: true, BlockQuads: 7},
+	} {
+		opts.SubjectGroups = true
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		// groups of 10 quads, the last 5 quads are written separately
+		for i := 0; i < 30; i += 10 {
+			if err := w.WriteSubjectGroup(ctx, all[i].Subject, all[i:i+10]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := w.WriteQuads(ctx, all[30:]); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+
+		got, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		} else if !reflect.DeepEqual(got, all) {
+			t.Fatalf("%+v: unexpected quads:\n%v\nvs\n%v", opts, got, all)
+		}
+
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.VerifyHashes(true)
+		var sizes []int
+		for {
+			subj, quads, err := r.ReadSubjectGroup(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			}
+			for _, q := range quads {
+				if q.Subject != subj {
+					t.Fatalf("%+v: unexpected subject in the group: %v vs %v", opts, q.Subject, subj)
+				}
+			}
+			sizes = append(sizes, len(quads))
+		}
+		if exp := []int{10, 10, 10, 1, 1, 1, 1, 1}; !reflect.DeepEqual(sizes, exp) {
+			t.Fatalf("%+v: unexpected group sizes: %v", opts, sizes)
+		}
+
+		r = pquads.NewReader(bytes.NewReader(data), 0)
+		for i := 0; i < 15; i++ {
+			if err = r.SkipQuad(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if q, err := r.ReadQuad(ctx); err != nil {
+			t.Fatal(err)
+		} else if q != all[15] {
+			t.Fatalf("%+v: unexpected quad after skip: %v vs %v", opts, q, all[15])
+		}
+	}
+
+	w := pquads.NewWriter(io.Discard, nil)
+	if err := w.WriteSubjectGroup(ctx, all[0].Subject, all[:10]); err == nil {
+		t.Fatal("expected an error without the option")
+	}
+	w = pquads.NewWriter(io.Discard, &pquads.Options{SubjectGroups: true})
+	if err := w.WriteSubjectGroup(ctx, all[0].Subject, all[5:15]); err == nil {
+		t.Fatal("expected an error for a different subject")
+	}
+}
@@ -0,0 +1,56 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestQuadHashes(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(20)
+	for _, opts := range []pquads.Options{
+		{QuadHashes: true},
+		{QuadHashes: true, Strict: true, Dictionary: true},
+	} {
+		data := writeQuads(t, &opts, in)
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.VerifyHashes(true)
+		for i := range in {
+			q, err := r.ReadQuad(ctx)
+			if err != nil {
+				t.Fatal(err)
+			} else if q != in[i] {
+				t.Fatalf("unexpected quad %d: %v", i, q)
+			} else if !bytes.Equal(r.QuadHash(), quad.HashQuad(q)) {
+				t.Fatalf("unexpected hash for quad %d: %x", i, r.QuadHash())
+			}
+		}
+	}
+
+	data := writeQuads(t, nil, in)
+	r := pquads.NewReader(bytes.NewReader(data), 0)
+	if _, err := r.ReadQuad(ctx); err != nil {
+		t.Fatal(err)
+	} else if r.QuadHash() != nil {
+		t.Fatal("expected no hash")
+	}
+
+	// corrupt an object without changing the size of the record
+	data = writeQuads(t, &pquads.Options{QuadHashes: true}, in)
+	i := bytes.LastIndex(data, []byte("object 19"))
+	data[i+len("object 1")] = '8'
+	r = pquads.NewReader(bytes.NewReader(data), 0)
+	if _, err := quad.ReadAll(ctx, r); err != nil {
+		t.Fatal("expected no error without verification")
+	}
+	r = pquads.NewReader(bytes.NewReader(data), 0)
+	r.VerifyHashes(true)
+	if _, err := quad.ReadAll(ctx, r); !errors.Is(err, pquads.ErrHashMismatch) {
+		t.Fatalf("expected hash mismatch, got: %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package pquads
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads/pio"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// IDWriter is a writer for id-based pquads files, intended for bulk loading of quads with values
+// that are already interned to integer ids.
+//
+// The file starts with the usual magic, version and a header with the ids flag set. Each following record
+// is an IDQuad message: either a dictionary entry (an id with a value) or a quad with ids of its values.
+// A value is marshaled only once, when its dictionary entry is written, and quads are written as plain
+// varints without any delta state. Id zero is reserved for a missing value (e.g. an empty label).
+type IDWriter struct {
+	pw  pio.RawWriter
+	buf []byte
+	err error
+	cl  io.Closer
+}
+
+// NewIDWriter creates an id-based quads encoder and writes all entries of the dictionary, ordered by id.
+// Dict can be nil, in which case values must be added with WriteValue.
+func NewIDWriter(w io.Writer, dict map[uint64]quad.Value) *IDWriter {
+	buf := make([]byte, 8)
+	copy(buf[:4], magic[:])
+	binary.LittleEndian.PutUint32(buf[4:], currentVersion)
+	if _, err := w.Write(buf); err != nil {
+		return &IDWriter{err: err}
+	}
+	pw := pio.NewWriter(w).(pio.RawWriter)
+	_, err := pw.WriteMsg(&Header{NotStrict: true, Ids: true})
+	qw := &IDWriter{pw: pw, err: err}
+	ids := make([]uint64, 0, len(dict))
+	for id := range dict {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if err = qw.WriteValue(id, dict[id]); err != nil {
+			break
+		}
+	}
+	return qw
+}
+
+// WriteValue writes a dictionary entry. It must be written before any quad that refers to the id.
+func (w *IDWriter) WriteValue(id uint64, v quad.Value) error {
+	if w.err != nil {
+		return w.err
+	} else if id == 0 {
+		return errors.New("dictionary id must not be zero")
+	} else if v == nil {
+		return fmt.Errorf("nil value for id %d", id)
+	}
+	_, w.err = w.pw.WriteMsg(&IDQuad{Value: &IDValue{Id: id, Value: MakeValue(v)}})
+	return w.err
+}
+
+// WriteIDQuad writes a quad with ids of its values, in the order of subject, predicate, object and label.
+// Ids are not checked against the dictionary.
+func (w *IDWriter) WriteIDQuad(ids [4]uint64) error {
+	if w.err != nil {
+		return w.err
+	} else if ids[0] == 0 || ids[1] == 0 || ids[2] == 0 {
+		return quad.ErrInvalid
+	}
+	buf := w.buf[:0]
+	for i, id := range ids {
+		if id != 0 {
+			buf = protowire.AppendTag(buf, protowire.Number(i+1), protowire.VarintType)
+			buf = protowire.AppendVarint(buf, id)
+		}
+	}
+	w.buf = buf
+	_, w.err = w.pw.WriteRaw(buf)
+	return w.err
+}
+
+func (w *IDWriter) SetCloser(c io.Closer) {
+	w.cl = c
+}
+
+func (w *IDWriter) Close() error {
+	if w.cl != nil {
+		return w.cl.Close()
+	}
+	return nil
+}
+
+var _ quad.ReadCloser = (*IDReader)(nil)
+
+// IDReader is a reader for id-based pquads files written by IDWriter.
+//
+// Dictionary entries are collected while reading and are decoded only when the value is requested.
+type IDReader struct {
+	r     *Reader
+	dict  map[uint64][]byte
+	cache map[uint64]quad.Value
+}
+
+// NewIDReader creates an id-based quads decoder. See NewReader for the description of arguments.
+func NewIDReader(r io.Reader, maxSize int) *IDReader {
+	qr, h := openReader(r, maxSize)
+	if qr.err == nil && !h.Ids {
+		qr.err = errors.New("pquads file doesn't contain id-based quads, use NewReader")
+	}
+	return &IDReader{
+		r:     qr,
+		dict:  make(map[uint64][]byte),
+		cache: make(map[uint64]quad.Value),
+	}
+}
+
+// ReadIDQuad reads ids of the next quad. It returns io.EOF if no quads are left.
+func (r *IDReader) ReadIDQuad() ([4]uint64, error) {
+	var ids [4]uint64
+	if r.r.err != nil {
+		return ids, r.r.err
+	}
+	for {
+		data, err := r.r.pr.ReadRaw()
+		if err != nil {
+			r.r.err = err
+			return ids, err
+		}
+		isQuad := true
+		for len(data) > 0 {
+			num, typ, n := protowire.ConsumeTag(data)
+			if n < 0 {
+				return ids, r.fail(protowire.ParseError(n))
+			}
+			data = data[n:]
+			switch {
+			case num >= 1 && num <= 4 && typ == protowire.VarintType:
+				ids[num-1], n = protowire.ConsumeVarint(data)
+			case num == 5 && typ == protowire.BytesType:
+				var v []byte
+				v, n = protowire.ConsumeBytes(data)
+				if n >= 0 {
+					isQuad = false
+					if err = r.addValue(v); err != nil {
+						return ids, r.fail(err)
+					}
+				}
+			default:
+				n = protowire.ConsumeFieldValue(num, typ, data)
+			}
+			if n < 0 {
+				return ids, r.fail(protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+		if isQuad {
+			return ids, nil
+		}
+	}
+}
+
+func (r *IDReader) fail(err error) error {
+	r.r.err = fmt.Errorf("corrupted id record: %w", err)
+	return r.r.err
+}
+
+// addValue stores an encoded IDValue message. The value itself is decoded lazily.
+func (r *IDReader) addValue(data []byte) error {
+	var (
+		id  uint64
+		val []byte
+	)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			id, n = protowire.ConsumeVarint(data)
+		case num == 2 && typ == protowire.BytesType:
+			val, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	if id == 0 {
+		return errors.New("dictionary id must not be zero")
+	}
+	// the buffer is reused by the reader
+	r.dict[id] = append([]byte(nil), val...)
+	delete(r.cache, id)
+	return nil
+}
+
+// Value returns a value for a given id from the dictionary. It returns nil for id zero.
+func (r *IDReader) Value(id uint64) (quad.Value, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	if v, ok := r.cache[id]; ok {
+		return v, nil
+	}
+	data, ok := r.dict[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown dictionary id: %d", id)
+	}
+	var pv Value
+	if err := pv.UnmarshalVT(data); err != nil {
+		return nil, err
+	}
+	v, err := nativeValue(&pv)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[id] = v
+	return v, nil
+}
+
+// ReadQuad reads the next quad and resolves its values using the dictionary.
+func (r *IDReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	ids, err := r.ReadIDQuad()
+	if err != nil {
+		return quad.Quad{}, err
+	}
+	var vals [4]quad.Value
+	for i, id := range ids {
+		if vals[i], err = r.Value(id); err != nil {
+			return quad.Quad{}, err
+		}
+	}
+	return quad.Quad{Subject: vals[0], Predicate: vals[1], Object: vals[2], Label: vals[3]}, nil
+}
+
+func (r *IDReader) SetCloser(c io.Closer) {
+	r.r.SetCloser(c)
+}
+
+func (r *IDReader) Close() error {
+	return r.r.Close()
+}
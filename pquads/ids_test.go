@@ -0,0 +1,87 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestIDWriter(t *testing.T) {
+	ctx := context.Background()
+	dict := map[uint64]quad.Value{
+		1: quad.IRI("alice"),
+		2: quad.IRI("knows"),
+		3: quad.IRI("bob"),
+		4: quad.String("Bob"),
+		5: quad.IRI("name"),
+	}
+	ids := [][4]uint64{
+		{1, 2, 3, 0},
+		{3, 5, 4, 0},
+		{3, 2, 1, 6},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewIDWriter(buf, dict)
+	for i, q := range ids {
+		if i == 2 {
+			if err := w.WriteValue(6, quad.IRI("graph")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.WriteIDQuad(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIDQuad([4]uint64{1, 0, 3, 0}); err != quad.ErrInvalid {
+		t.Fatalf("expected an error, got: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	r := pquads.NewIDReader(bytes.NewReader(data), 0)
+	for _, exp := range ids {
+		got, err := r.ReadIDQuad()
+		if err != nil {
+			t.Fatal(err)
+		} else if got != exp {
+			t.Fatalf("unexpected ids: %v vs %v", got, exp)
+		}
+	}
+	if _, err := r.ReadIDQuad(); err != io.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+	if v, err := r.Value(4); err != nil || v != quad.String("Bob") {
+		t.Fatalf("unexpected value: %v, %v", v, err)
+	}
+	if _, err := r.Value(7); err == nil {
+		t.Fatal("expected an error for unknown id")
+	}
+
+	out, err := quad.ReadAll(ctx, pquads.NewIDReader(bytes.NewReader(data), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []quad.Quad{
+		quad.MakeIRI("alice", "knows", "bob", ""),
+		{Subject: quad.IRI("bob"), Predicate: quad.IRI("name"), Object: quad.String("Bob")},
+		quad.MakeIRI("bob", "knows", "alice", "graph"),
+	}
+	if !reflect.DeepEqual(exp, out) {
+		t.Fatalf("unexpected quads:\n%v\n%v", exp, out)
+	}
+
+	if _, err := pquads.NewReader(bytes.NewReader(data), 0).ReadQuad(ctx); err == nil {
+		t.Fatal("expected an error for id-based file")
+	}
+	if _, err := pquads.NewIDReader(bytes.NewReader(writeQuads(t, nil, exp)), 0).ReadIDQuad(); err == nil {
+		t.Fatal("expected an error for value-based file")
+	}
+}
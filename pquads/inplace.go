@@ -0,0 +1,145 @@
+package pquads
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// CompactInPlace converts a file written with the Full option to a compacted one, overwriting it in place
+// and truncating it to the new size. The file handle must also implement Truncate(size int64) error, as *os.File does.
+// See NewReader for the description of maxSize.
+//
+// All other options of the file are preserved. Checkpoints are preserved as well, at the same quads, thus the dictionary
+// and other decoding state is reset at the same points as in the original file: a compacted record is never larger
+// than the full one, and the data is always written behind the read position. A block index is rewritten at
+// the end of the file, with blocks starting at the same quads as in the original file.
+//
+// The file is left corrupted if the compaction fails midway, for example because of an I/O error.
+// It requires an exclusive handle: the file must not be accessed by anyone else during the compaction.
+func CompactInPlace(rws io.ReadWriteSeeker, maxSize int) error {
+	tr, ok := rws.(interface {
+		Truncate(size int64) error
+	})
+	if !ok {
+		return errors.New("file doesn't support truncation")
+	}
+	size, err := rws.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	idx, _, err := readIndex(seekReaderAt{rws}, size)
+	if err == errNoIndex {
+		idx = nil
+	} else if err != nil {
+		return err
+	}
+	f := &inPlaceFile{f: rws}
+	r := NewReader(&inPlaceReader{f}, maxSize)
+	if r.err != nil {
+		return r.err
+	} else if r.zr != nil {
+		return errors.New("compressed files cannot be compacted in place")
+	} else if !r.opts.Full {
+		return errors.New("file is already compacted")
+	}
+	opts := r.opts
+	opts.Full = false
+	// values were already validated, and strings with invalid UTF-8 can only come from files that preserve them
+	opts.PreserveInvalidUTF8 = true
+	var blocks []*BlockIndex_Block // starts of blocks after the first one
+	if idx != nil {
+		// blocks are started explicitly, at the same quads as in the original file
+		opts.BlockQuads, blocks = math.MaxInt32, idx.Blocks[1:]
+	}
+
+	ctx := context.Background()
+	bw := bufio.NewWriter(&inPlaceWriter{f})
+	w := NewWriter(bw, &opts)
+	for i := 0; ; i++ {
+		cp, hasCp := r.cp, r.hasCp
+		q, op, err := r.ReadChange(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("read quad %d: %w", i, err)
+		}
+		// the reader passed a checkpoint before this quad
+		atCp := r.hasCp && (!hasCp || r.cp != cp)
+		for len(blocks) != 0 && blocks[0].Ordinal <= uint64(i) {
+			atCp, blocks = true, blocks[1:]
+		}
+		if atCp {
+			if err = w.Checkpoint(); err != nil {
+				return err
+			}
+		}
+		if opts.Sources {
+			if err = w.SetSource(r.srcName); err != nil {
+				return err
+			}
+		}
+		if err = w.writeChange(ctx, q, op, r.qver, r.flags); err != nil {
+			return fmt.Errorf("write quad %d: %w", i, err)
+		}
+	}
+	f.eof = true
+	if err := w.Close(); err != nil {
+		return err
+	} else if err = bw.Flush(); err != nil {
+		return err
+	}
+	return tr.Truncate(f.wpos)
+}
+
+// seekReaderAt implements io.ReaderAt for a seekable file that is not accessed concurrently.
+type seekReaderAt struct {
+	f io.ReadSeeker
+}
+
+func (r seekReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := r.f.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.f, p)
+}
+
+// inPlaceFile tracks separate read and write positions in a single file.
+type inPlaceFile struct {
+	f    io.ReadWriteSeeker
+	rpos int64
+	wpos int64
+	eof  bool // all data was read
+}
+
+type inPlaceReader struct {
+	*inPlaceFile
+}
+
+func (r *inPlaceReader) Read(p []byte) (int, error) {
+	if _, err := r.f.Seek(r.rpos, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := r.f.Read(p)
+	r.rpos += int64(n)
+	return n, err
+}
+
+type inPlaceWriter struct {
+	*inPlaceFile
+}
+
+func (w *inPlaceWriter) Write(p []byte) (int, error) {
+	if !w.eof && w.wpos+int64(len(p)) > w.rpos {
+		return 0, errors.New("in-place compaction would overwrite unread data")
+	}
+	if _, err := w.f.Seek(w.wpos, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := w.f.Write(p)
+	w.wpos += int64(n)
+	return n, err
+}
@@ -0,0 +1,158 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestCompactInPlace(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(5000)
+	for _, opts := range []pquads.Options{
+		{Full: true},
+		{Full: true, Strict: true, QuadHashes: true},
+		{Full: true, BlockQuads: 100},
+	} {
+		full := writeQuads(t, &opts, in)
+		path := filepath.Join(t.TempDir(), "quads.pq")
+		if err := os.WriteFile(path, full, 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pquads.CompactInPlace(f, 0)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the block index is preserved
+		opts.Full = false
+		if exp := writeQuads(t, &opts, in); len(data) != len(exp) || len(data) >= len(full) {
+			t.Fatalf("unexpected size: %d vs %d (full: %d)", len(data), len(exp), len(full))
+		}
+		f, err = os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(f, 0))
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", opts)
+		}
+		if opts.BlockQuads != 0 {
+			ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+			if err != nil {
+				t.Fatal(err)
+			} else if n := ir.NumBlocks(); n != len(in)/opts.BlockQuads {
+				t.Fatalf("unexpected number of blocks: %d", n)
+			}
+			out, err = quad.ReadAll(ctx, ir.Block(3))
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(in[300:400], out) {
+				t.Fatalf("wrong quads in block:\n%v", out)
+			}
+		}
+
+		f, _ = os.OpenFile(path, os.O_RDWR, 0)
+		if err = pquads.CompactInPlace(f, 0); err == nil {
+			t.Fatal("expected an error for compacted file")
+		}
+		f.Close()
+	}
+}
+
+func TestCompactInPlaceCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	// values change on every quad, and times are far apart, thus only checkpoints keep them small
+	var times []quad.Quad
+	for i := 0; i < 500; i++ {
+		ts := time.Unix(int64(i), 0).UTC()
+		if i%2 == 1 {
+			ts = ts.Add(250 * 365 * 24 * time.Hour)
+		}
+		times = append(times, quad.Quad{
+			Subject:   quad.IRI(fmt.Sprintf("s%d", i)),
+			Predicate: quad.IRI(fmt.Sprintf("p%d", i)),
+			Object:    quad.Time(ts),
+		})
+	}
+	for _, c := range []struct {
+		opts  pquads.Options
+		in    []quad.Quad
+		every int
+	}{
+		{pquads.Options{Full: true, TimeDeltas: true}, times, 1},
+		{pquads.Options{Full: true, TimeDeltas: true, Dictionary: true}, makeTimeQuads(2000), 7},
+		{pquads.Options{Full: true, Dictionary: true}, makeQuads(2000), 7},
+		{pquads.Options{Full: true, Dictionary: true, Strict: true, BlockQuads: 100}, makeQuads(2000), 30},
+	} {
+		full := writeCheckpointed(t, &c.opts, c.in, c.every)
+		path := filepath.Join(t.TempDir(), "quads.pq")
+		if err := os.WriteFile(path, full, 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pquads.CompactInPlace(f, 0)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%+v: %v", c.opts, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := c.opts
+		opts.Full = false
+		if exp := writeCheckpointed(t, &opts, c.in, c.every); !bytes.Equal(data, exp) {
+			t.Fatalf("%+v: checkpoints were not preserved: %d vs %d bytes", c.opts, len(data), len(exp))
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(c.in, out) {
+			t.Fatalf("%+v: corrupted quads", c.opts)
+		}
+	}
+}
+
+// writeCheckpointed writes quads with an explicit checkpoint after every given number of quads.
+func writeCheckpointed(t testing.TB, opts *pquads.Options, quads []quad.Quad, every int) []byte {
+	ctx := context.Background()
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, opts)
+	for i, q := range quads {
+		if i != 0 && i%every == 0 {
+			if err := w.Checkpoint(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.WriteQuad(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
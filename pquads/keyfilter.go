@@ -0,0 +1,148 @@
+package pquads
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cayleygraph/quad"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// KeySet is a set of values for NewKeyFilterReader.
+type KeySet interface {
+	// Contains reports whether a value is in the set. Approximate sets may report values that were never added.
+	Contains(v quad.Value) bool
+}
+
+// KeyMap is an exact KeySet backed by a map. Values are compared as map keys.
+type KeyMap map[quad.Value]bool
+
+func (m KeyMap) Contains(v quad.Value) bool {
+	return m[v]
+}
+
+var _ KeySet = (*BloomKeySet)(nil)
+
+// BloomKeySet is an approximate KeySet backed by a Bloom filter, for sets that are too large to keep in memory.
+// It never misses a value that was added, but may report a value that was not added as present (a false positive).
+// Values are compared by their hashes, see quad.HashOf.
+type BloomKeySet struct {
+	f *bloomFilter
+}
+
+// NewBloomKeySet creates an empty Bloom filter for an expected number of values n (1<<20 if zero) and
+// the false positive rate after adding this many values (0.01 if zero). It uses about 1.44*log2(1/rate) bits
+// per value, and the false positive rate grows quickly if more values are added.
+func NewBloomKeySet(n int, rate float64) *BloomKeySet {
+	if n <= 0 {
+		n = 1 << 20
+	}
+	if rate <= 0 || rate >= 1 {
+		rate = 0.01
+	}
+	return &BloomKeySet{f: newBloomFilter(n, rate)}
+}
+
+// Add adds a value to the set.
+func (s *BloomKeySet) Add(v quad.Value) {
+	s.f.add(quad.HashOf(v))
+}
+
+func (s *BloomKeySet) Contains(v quad.Value) bool {
+	return s.f.contains(quad.HashOf(v))
+}
+
+var _ quad.ReadCloser = (*KeyFilterReader)(nil)
+
+// KeyFilterReader is a quad reader that only returns quads with a value from a key set in a given direction.
+type KeyFilterReader struct {
+	r    *Reader
+	keys KeySet
+	num  protowire.Number // field of the direction
+	err  error
+
+	last    []byte     // encoded key of the last quad
+	key     quad.Value // decoded key of the last quad
+	matched bool       // the last key is in the set
+}
+
+// NewKeyFilterReader wraps a reader to only return quads that have a value from the key set in a given direction,
+// for example to extract descriptions of a set of subjects from a large file. Quads without a value in this
+// direction (like quads in the default graph for quad.Label) are skipped. Use KeyMap for an exact set of keys,
+// or BloomKeySet for sets that don't fit in memory.
+//
+// Like NewDropPredicateReader, it projects quads on the key first: only the field of the given direction is decoded,
+// and only when it differs from the one of the previous record, which makes skipping runs of quads with the same
+// key cheap. Other values of skipped quads are only decoded if they are carried over to the next quads in compacted
+// files. Files with a dictionary (including a language tag dictionary) or subject groups are decoded completely.
+// Hashes of skipped quads are not verified (see Reader.VerifyHashes).
+//
+// With an approximate set, the reader also returns quads with keys that are false positives of the set.
+// They can be filtered by checking the key of each returned quad against the exact set, if it's available
+// (for example, in a database), which is only needed for a small fraction of quads.
+//
+// The direction must be one of quad.Subject, quad.Predicate, quad.Object or quad.Label, otherwise the reader
+// fails with an error.
+func NewKeyFilterReader(r *Reader, keys KeySet, dir quad.Direction) *KeyFilterReader {
+	k := &KeyFilterReader{r: r, keys: keys, num: protowire.Number(dir)}
+	if dir < quad.Subject || dir > quad.Label {
+		k.err = fmt.Errorf("unsupported key direction: %v", dir)
+	}
+	return k
+}
+
+func (k *KeyFilterReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	r := k.r
+	if k.err != nil {
+		return quad.Quad{}, k.err
+	} else if r.opts.valueState() || r.opts.SubjectGroups {
+		for {
+			q, err := r.ReadQuad(ctx)
+			if err != nil {
+				return q, err
+			} else if v := q.Get(quad.Direction(k.num)); v != nil && k.keys.Contains(v) {
+				return q, nil
+			}
+		}
+	}
+	for {
+		if r.err != nil {
+			return quad.Quad{}, r.err
+		}
+		var data []byte
+		if data, r.err = r.readRaw(); r.err != nil {
+			return quad.Quad{}, r.err
+		}
+		if r.skipNonQuad(data) {
+			if hasField(data, checkpointField) {
+				// checkpoints reset the carried over values, other records don't affect compaction
+				k.last, k.key, k.matched = k.last[:0], nil, false
+			}
+			continue
+		}
+		b, ok := fieldBytes(data, k.num)
+		switch {
+		case !ok && k.num == 4:
+			// labels are not carried over
+			k.last, k.key, k.matched = k.last[:0], nil, false
+		case ok && (k.key == nil || !bytes.Equal(b, k.last)):
+			if k.key, r.err = r.decodeField(b, k.num); r.err != nil {
+				return quad.Quad{}, r.err
+			}
+			k.last = append(k.last[:0], b...)
+			k.matched = k.key != nil && k.keys.Contains(k.key)
+		}
+		if k.matched {
+			r.next = data
+			return r.ReadQuad(ctx)
+		}
+		if r.err = r.dropQuad(data, k.num, k.key); r.err != nil {
+			return quad.Quad{}, r.err
+		}
+	}
+}
+
+func (k *KeyFilterReader) Close() error {
+	return k.r.Close()
+}
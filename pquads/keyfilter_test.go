@@ -0,0 +1,119 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestKeyFilterReader(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(300)
+	for i := range in {
+		if i%7 == 0 {
+			in[i].Label = quad.IRI(fmt.Sprintf("g%d", i%3))
+		}
+	}
+	keys := pquads.KeyMap{
+		quad.IRI("s3"): true, quad.IRI("s17"): true, quad.IRI("s29"): true,
+		quad.IRI("p1"): true, quad.String("object 42"): true, quad.IRI("g1"): true,
+	}
+	for _, opts := range []pquads.Options{
+		{},
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{SubjectGroups: true},
+		{Sources: true},
+		{BlockQuads: 7, Strict: true, Full: true},
+	} {
+		data := writeCursorFile(t, &opts, in)
+		for _, dir := range quad.Directions {
+			var exp []quad.Quad
+			for _, q := range in {
+				if keys[q.Get(dir)] {
+					exp = append(exp, q)
+				}
+			}
+			r := pquads.NewKeyFilterReader(pquads.NewReader(bytes.NewReader(data), 0), keys, dir)
+			out, err := quad.ReadAll(ctx, r)
+			if err != nil {
+				t.Fatalf("%+v, %v: %v", opts, dir, err)
+			} else if len(exp) == 0 || !reflect.DeepEqual(out, exp) {
+				t.Fatalf("%+v, %v: wrong quads: %d vs %d\n%v", opts, dir, len(out), len(exp), out)
+			}
+		}
+	}
+}
+
+func TestKeyFilterBloom(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(1000)
+	exact := make(pquads.KeyMap)
+	bloom := pquads.NewBloomKeySet(50, 0.01)
+	for i := 0; i < 100; i += 2 {
+		v := quad.IRI(fmt.Sprintf("s%d", i))
+		exact[v] = true
+		bloom.Add(v)
+	}
+	data := writeQuads(t, nil, in)
+	r := pquads.NewKeyFilterReader(pquads.NewReader(bytes.NewReader(data), 0), bloom, quad.Subject)
+	out, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no key is missed, and false positives can be filtered by the exact set
+	var got []quad.Quad
+	for _, q := range out {
+		if exact[q.Subject] {
+			got = append(got, q)
+		}
+	}
+	var exp []quad.Quad
+	for _, q := range in {
+		if exact[q.Subject] {
+			exp = append(exp, q)
+		}
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("wrong quads: %d vs %d", len(got), len(exp))
+	} else if fp := len(out) - len(got); fp > len(in)/10 {
+		t.Fatalf("too many false positives: %d", fp)
+	}
+}
+
+func TestKeyFilterSources(t *testing.T) {
+	ctx := context.Background()
+	// source records don't reset values carried over to the next quad
+	data, in := writeSourceSwitchFile(t)
+	for _, c := range []struct {
+		dir quad.Direction
+		key quad.Value
+		exp []quad.Quad
+	}{
+		{quad.Subject, quad.IRI("s"), in},
+		{quad.Predicate, quad.IRI("bad"), in[:2]},
+	} {
+		r := pquads.NewKeyFilterReader(pquads.NewReader(bytes.NewReader(data), 0), pquads.KeyMap{c.key: true}, c.dir)
+		out, err := quad.ReadAll(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(out, c.exp) {
+			t.Fatalf("%v: wrong quads:\n%v", c.dir, out)
+		}
+	}
+}
+
+func TestKeyFilterDirection(t *testing.T) {
+	data := writeQuads(t, nil, makeQuads(10))
+	r := pquads.NewKeyFilterReader(pquads.NewReader(bytes.NewReader(data), 0), pquads.KeyMap{}, quad.Any)
+	if _, err := r.ReadQuad(context.Background()); err == nil || err == io.EOF {
+		t.Fatalf("expected an error for an unsupported direction, got: %v", err)
+	}
+}
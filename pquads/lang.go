@@ -0,0 +1,141 @@
+package pquads
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultLangTags is a set of common language tags that can be passed to Options.LangTags.
+//
+// Tags are stored in the header of each file, thus files remain readable if this list changes.
+var DefaultLangTags = []string{
+	"en", "de", "fr", "es", "it", "pt", "nl", "sv", "pl", "ru", "uk", "cs",
+	"ja", "zh", "ko", "ar", "fa", "he", "hi", "tr",
+}
+
+// recordValues calls fn for each value written in full in a record, in the order they are assigned tag numbers
+// and time deltas: values of a quad in the order of directions followed by the version, and then values of each
+// quad in a group.
+func recordValues(m proto.Message, fn func(*Value) error) error {
+	each := func(vals ...*Value) error {
+		for _, v := range vals {
+			if v == nil {
+				continue
+			} else if err := fn(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch m := m.(type) {
+	case *WireQuad:
+		if err := each(m.Subject, m.Predicate, m.Object, m.Label, m.Version); err != nil {
+			return err
+		}
+		for _, e := range m.Group {
+			if err := recordValues(e, fn); err != nil {
+				return err
+			}
+		}
+	case *StrictQuad:
+		if err := each(m.Object, m.Version); err != nil {
+			return err
+		}
+		for _, e := range m.Group {
+			if err := recordValues(e, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// langStrings calls fn for each language string in a record, in the order of recordValues.
+func langStrings(m proto.Message, fn func(*Value_LangString) error) error {
+	return recordValues(m, func(v *Value) error {
+		if ls, ok := v.GetValue().(*Value_LangStr); ok && ls.LangStr != nil {
+			return fn(ls.LangStr)
+		}
+		return nil
+	})
+}
+
+// resetLangs resets the language tag dictionary to the tags from the header, as done by the checkpoint.
+func (w *Writer) resetLangs() {
+	if !w.opts.LangDictionary {
+		return
+	}
+	w.langs = make(map[string]uint64, len(w.opts.LangTags))
+	for i, t := range w.opts.LangTags {
+		w.langs[t] = uint64(i) + 1
+	}
+}
+
+// encodeLang replaces the tag of a language string with a reference, if the tag is in the dictionary.
+// Otherwise, the tag is written in full and added to the dictionary.
+func (w *Writer) encodeLang(ls *Value_LangString) error {
+	if id, ok := w.langs[ls.Lang]; ok {
+		ls.Lang, ls.LangRef = "", id
+	} else {
+		w.langs[ls.Lang] = uint64(len(w.langs)) + 1
+	}
+	return nil
+}
+
+// resetLangs resets the language tag dictionary to the tags from the header, as done by the checkpoint.
+func (r *Reader) resetLangs() {
+	if r.opts.LangDictionary {
+		r.langs = append(r.langs[:0], r.opts.LangTags...)
+	}
+}
+
+// decodeValues restores tags of all language strings in a record, if the file has a language tag dictionary,
+// and timestamps encoded as time deltas. It also verifies that values encoded by codecs can be decoded.
+func (r *Reader) decodeValues(m proto.Message) error {
+	r.recLangs, r.recTime, r.recHasTime = len(r.langs), r.lastTime, r.hasTime
+	if r.opts.LangDictionary {
+		if err := langStrings(m, r.decodeLang); err != nil {
+			return err
+		}
+	}
+	// also rejects time deltas in files without them and encoded values that cannot be decoded,
+	// which cannot be converted to native values
+	return recordValues(m, r.decodeValue)
+}
+
+func (r *Reader) decodeLang(ls *Value_LangString) error {
+	if ls.LangRef == 0 {
+		r.langs = append(r.langs, ls.Lang)
+		return nil
+	} else if ls.Lang != "" || ls.LangRef > uint64(len(r.langs)) {
+		return fmt.Errorf("invalid language tag reference at quad %d: %d", r.n, ls.LangRef)
+	}
+	ls.Lang, ls.LangRef = r.langs[ls.LangRef-1], 0
+	return nil
+}
+
+// uniqueLangTags removes repeated tags from a list, keeping the first occurrence of each.
+func uniqueLangTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func equalLangTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+// makeLangQuads makes quads with a label in one of a few languages each.
+func makeLangQuads(n int) []quad.Quad {
+	langs := []string{"en", "fr", "de-CH", "en", "zh-Hant-TW"}
+	quads := make([]quad.Quad, 0, n)
+	for i := 0; i < n; i++ {
+		quads = append(quads, quad.Quad{
+			Subject:   quad.IRI(fmt.Sprintf("s%d", i/5)),
+			Predicate: quad.IRI("label"),
+			Object:    quad.LangString{Value: quad.String(fmt.Sprintf("label %d", i)), Lang: langs[i%len(langs)]},
+		})
+	}
+	return quads
+}
+
+func TestLangDictionary(t *testing.T) {
+	ctx := context.Background()
+	in := makeLangQuads(100)
+	for _, opts := range []pquads.Options{
+		{LangDictionary: true},
+		{LangDictionary: true, LangTags: pquads.DefaultLangTags},
+		{LangDictionary: true, LangTags: []string{"fr", "fr", "en"}},
+		{LangDictionary: true, Dictionary: true},
+		{LangDictionary: true, Strict: true, BlockQuads: 7},
+		{LangDictionary: true, Full: true, QuadHashes: true},
+	} {
+		data := writeQuads(t, &opts, in)
+		popts := opts
+		popts.LangDictionary, popts.LangTags = false, nil
+		if plain := len(writeQuads(t, &popts, in)); len(data) >= plain {
+			t.Errorf("%+v: file is not smaller: %d vs %d", opts, len(data), plain)
+		}
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.VerifyHashes(true)
+		out, err := quad.ReadAll(ctx, r)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		} else if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%+v: wrong quads:\n%v", opts, out)
+		}
+	}
+}
+
+func TestLangDictionaryWire(t *testing.T) {
+	ctx := context.Background()
+	in := makeLangQuads(10)
+	data := writeQuads(t, &pquads.Options{Full: true, LangDictionary: true, LangTags: []string{"fr"}}, in)
+	rr := pquads.NewRecordReader(bytes.NewReader(data), 0)
+	defer rr.Close()
+	rec, err := rr.ReadRecord(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if h := rec.Header; !h.LangDictionary || !reflect.DeepEqual(h.LangTags, []string{"fr"}) {
+		t.Fatalf("unexpected header: %v", h)
+	}
+	// fr is seeded, others are numbered in order of the first occurrence
+	exp := []struct {
+		lang string
+		ref  uint64
+	}{
+		{"en", 0}, {"", 1}, {"de-CH", 0}, {"", 2}, {"zh-Hant-TW", 0},
+		{"", 2}, {"", 1}, {"", 3}, {"", 2}, {"", 4},
+	}
+	for i := 0; ; i++ {
+		rec, err := rr.ReadRecord(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		} else if rec.Type != pquads.RecordQuad {
+			continue
+		}
+		ls := rec.Wire.Object.GetLangStr()
+		if ls.Lang != exp[i].lang || ls.LangRef != exp[i].ref {
+			t.Fatalf("quad %d: unexpected language tag: %q, ref %d", i, ls.Lang, ls.LangRef)
+		}
+	}
+}
+
+func TestLangDictionaryGroups(t *testing.T) {
+	ctx := context.Background()
+	in := makeLangQuads(50)
+	opts := &pquads.Options{LangDictionary: true, SubjectGroups: true, BlockQuads: 10}
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, opts)
+	for i := 0; i < len(in); i += 5 {
+		if err := w.WriteSubjectGroup(ctx, in[i].Subject, in[i:i+5]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(out, in) {
+		t.Fatalf("wrong quads:\n%v", out)
+	}
+	// each block starts with a fresh tag dictionary
+	ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = quad.ReadAll(ctx, ir.Block(2))
+	if err != nil {
+		t.Fatal(err)
+	} else if i := ir.BlockOrdinal(2); !reflect.DeepEqual(out, in[i:i+len(out)]) {
+		t.Fatalf("wrong quads in block:\n%v", out)
+	}
+}
+
+func TestLangDictionaryCursor(t *testing.T) {
+	ctx := context.Background()
+	in := makeLangQuads(20)
+	data := writeQuads(t, &pquads.Options{LangDictionary: true, LangTags: []string{"en"}}, in)
+	r := pquads.NewReader(bytes.NewReader(data), 0)
+	var out []quad.Quad
+	for i := 0; i < 4; i++ {
+		q, err := r.ReadQuad(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, q)
+	}
+	cur, err := r.Cursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = pquads.ResumeReader(bytes.NewReader(data), cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out = append(out, rest...); !reflect.DeepEqual(out, in) {
+		t.Fatalf("wrong quads after resuming:\n%v", out)
+	}
+}
@@ -0,0 +1,196 @@
+package pquads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/voc/xsd"
+)
+
+// LiteralValidator checks if s is a valid lexical form for a specific datatype.
+type LiteralValidator func(s string) error
+
+var literalValidators = make(map[quad.IRI]LiteralValidator)
+
+// RegisterLiteralValidator registers a validator for lexical forms of typed literals with a given datatype.
+// It replaces any validator registered for the same datatype.
+//
+// If fnc is nil, the validator for the datatype will be removed.
+func RegisterLiteralValidator(dataType quad.IRI, fnc LiteralValidator) {
+	dataType = dataType.Full()
+	if fnc == nil {
+		delete(literalValidators, dataType)
+	} else {
+		literalValidators[dataType] = fnc
+	}
+}
+
+// ValidateLiteral checks that a lexical form of a typed literal is valid for its datatype.
+// Literals with unknown datatypes are always valid.
+func ValidateLiteral(v quad.TypedString) error {
+	fnc := literalValidators[v.Type.Full()]
+	if fnc == nil {
+		return nil
+	}
+	return fnc(string(v.Value))
+}
+
+// LiteralError describes an object literal with a lexical form that is invalid for its datatype.
+type LiteralError struct {
+	Ordinal int // zero-based index of the quad in the file
+	Quad    quad.Quad
+	Err     error
+}
+
+func (e *LiteralError) Error() string {
+	return fmt.Sprintf("quad %d: invalid literal %v: %v", e.Ordinal, e.Quad.Object, e.Err)
+}
+
+func (e *LiteralError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateLiterals reads all quads from a pquads file and checks lexical forms of object literals against
+// their datatypes, using validators registered with RegisterLiteralValidator.
+//
+// It returns all violations that were found. An error is only returned if the file cannot be read.
+func ValidateLiterals(r io.Reader, maxSize int) ([]LiteralError, error) {
+	ctx := context.Background()
+	qr := NewReader(r, maxSize)
+	defer qr.Close()
+	var out []LiteralError
+	for i := 0; ; i++ {
+		q, err := qr.ReadQuad(ctx)
+		if err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return out, fmt.Errorf("quad %d: %w", i, err)
+		}
+		ts, ok := q.Object.(quad.TypedString)
+		if !ok {
+			continue
+		}
+		if err = ValidateLiteral(ts); err != nil {
+			out = append(out, LiteralError{Ordinal: i, Quad: q, Err: err})
+		}
+	}
+}
+
+var (
+	reDecimal  = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+	reDouble   = regexp.MustCompile(`^([+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([Ee][+-]?[0-9]+)?|[+-]?INF|NaN)$`)
+	reInteger  = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	reDate     = regexp.MustCompile(`^-?([0-9]{4,})-([0-9]{2})-([0-9]{2})(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+	reDateTime = regexp.MustCompile(`^-?([0-9]{4,})-([0-9]{2})-([0-9]{2})T([0-9]{2}):([0-9]{2}):([0-9]{2})(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+)
+
+func init() {
+	RegisterLiteralValidator(xsd.Boolean, validateBool)
+	RegisterLiteralValidator(xsd.Decimal, validateRegexp(reDecimal))
+	RegisterLiteralValidator(xsd.Double, validateRegexp(reDouble))
+	RegisterLiteralValidator(xsd.Float, validateRegexp(reDouble))
+	RegisterLiteralValidator(xsd.Date, validateDate)
+	RegisterLiteralValidator(xsd.DateTime, validateDateTime)
+
+	RegisterLiteralValidator(xsd.Integer, validateInteger("", ""))
+	RegisterLiteralValidator(xsd.NonPositiveInteger, validateInteger("", "0"))
+	RegisterLiteralValidator(xsd.NegativeInteger, validateInteger("", "-1"))
+	RegisterLiteralValidator(xsd.NonNegativeInteger, validateInteger("0", ""))
+	RegisterLiteralValidator(xsd.PositiveInteger, validateInteger("1", ""))
+	RegisterLiteralValidator(xsd.Long, validateInteger("-9223372036854775808", "9223372036854775807"))
+	RegisterLiteralValidator(xsd.Int, validateInteger("-2147483648", "2147483647"))
+	RegisterLiteralValidator(xsd.Short, validateInteger("-32768", "32767"))
+	RegisterLiteralValidator(xsd.Byte, validateInteger("-128", "127"))
+	RegisterLiteralValidator(xsd.UnsignedLong, validateInteger("0", "18446744073709551615"))
+	RegisterLiteralValidator(xsd.UnsignedInt, validateInteger("0", "4294967295"))
+	RegisterLiteralValidator(xsd.UnsignedShort, validateInteger("0", "65535"))
+	RegisterLiteralValidator(xsd.UnsignedByte, validateInteger("0", "255"))
+}
+
+func validateBool(s string) error {
+	switch s {
+	case "true", "false", "1", "0":
+		return nil
+	}
+	return fmt.Errorf("invalid boolean: %q", s)
+}
+
+func validateRegexp(re *regexp.Regexp) LiteralValidator {
+	return func(s string) error {
+		if !re.MatchString(s) {
+			return fmt.Errorf("invalid lexical form: %q", s)
+		}
+		return nil
+	}
+}
+
+// validateInteger returns a validator for integers in an inclusive range. Empty bound means no limit.
+func validateInteger(min, max string) LiteralValidator {
+	var lo, hi *big.Int
+	if min != "" {
+		lo, _ = new(big.Int).SetString(min, 10)
+	}
+	if max != "" {
+		hi, _ = new(big.Int).SetString(max, 10)
+	}
+	return func(s string) error {
+		if !reInteger.MatchString(s) {
+			return fmt.Errorf("invalid integer: %q", s)
+		}
+		v, _ := new(big.Int).SetString(s, 10)
+		if (lo != nil && v.Cmp(lo) < 0) || (hi != nil && v.Cmp(hi) > 0) {
+			return fmt.Errorf("integer out of range: %s", s)
+		}
+		return nil
+	}
+}
+
+func validateDate(s string) error {
+	m := reDate.FindStringSubmatch(s)
+	if m == nil {
+		return fmt.Errorf("invalid date: %q", s)
+	}
+	return checkDate(s, m[1], m[2], m[3])
+}
+
+func validateDateTime(s string) error {
+	m := reDateTime.FindStringSubmatch(s)
+	if m == nil {
+		return fmt.Errorf("invalid dateTime: %q", s)
+	}
+	if err := checkDate(s, m[1], m[2], m[3]); err != nil {
+		return err
+	}
+	h, _ := strconv.Atoi(m[4])
+	mi, _ := strconv.Atoi(m[5])
+	sec, _ := strconv.Atoi(m[6])
+	// 24:00:00 is allowed by XSD as the end of the day
+	if mi > 59 || sec > 59 || h > 24 || (h == 24 && (mi != 0 || sec != 0 || strings.Trim(m[7], ".0") != "")) {
+		return fmt.Errorf("invalid time in dateTime: %q", s)
+	}
+	return nil
+}
+
+func checkDate(s, year, month, day string) error {
+	y, _ := strconv.Atoi(year)
+	mo, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	if mo < 1 || mo > 12 || d < 1 {
+		return fmt.Errorf("invalid date: %q", s)
+	}
+	// use a leap year for years that don't fit into time.Date range
+	if len(year) > 4 {
+		y = 2000
+	}
+	if d > time.Date(y, time.Month(mo)+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return fmt.Errorf("invalid date: %q", s)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package pquads_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+var literalCases = []struct {
+	typ   quad.IRI
+	val   string
+	valid bool
+}{
+	{"xsd:integer", "-42", true},
+	{"xsd:integer", "abc", false},
+	{"http://www.w3.org/2001/XMLSchema#integer", "4.2", false},
+	{"xsd:byte", "127", true},
+	{"xsd:byte", "128", false},
+	{"xsd:unsignedInt", "-1", false},
+	{"xsd:decimal", "+.5", true},
+	{"xsd:decimal", "1e3", false},
+	{"xsd:double", "1.5E-3", true},
+	{"xsd:double", "INF", true},
+	{"xsd:float", "inf", false},
+	{"xsd:boolean", "1", true},
+	{"xsd:boolean", "yes", false},
+	{"xsd:date", "2020-02-29", true},
+	{"xsd:date", "2021-02-29", false},
+	{"xsd:date", "2021-13-01Z", false},
+	{"xsd:dateTime", "2021-01-01T10:20:30.5+02:00", true},
+	{"xsd:dateTime", "2021-01-01T24:00:00", true},
+	{"xsd:dateTime", "2021-01-01T10:61:00", false},
+	{"xsd:dateTime", "2021-01-01", false},
+	{"http://example.org/unknown", "anything", true},
+}
+
+func TestValidateLiterals(t *testing.T) {
+	var (
+		in  []quad.Quad
+		exp []int
+	)
+	for i, c := range literalCases {
+		in = append(in, quad.Quad{
+			Subject:   quad.IRI("s"),
+			Predicate: quad.IRI("p"),
+			Object:    quad.TypedString{Value: quad.String(c.val), Type: c.typ},
+		})
+		if !c.valid {
+			exp = append(exp, i)
+		}
+	}
+	in = append(in, quad.MakeIRI("s", "p", "o", ""))
+	errs, err := pquads.ValidateLiterals(bytes.NewReader(writeQuads(t, nil, in)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != len(exp) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for i, e := range errs {
+		if e.Ordinal != exp[i] || e.Quad != in[exp[i]] {
+			t.Errorf("unexpected error: %v", &e)
+		}
+	}
+}
+
+func TestRegisterLiteralValidator(t *testing.T) {
+	const typ = quad.IRI("http://example.org/even")
+	errOdd := errors.New("odd")
+	pquads.RegisterLiteralValidator(typ, func(s string) error {
+		if len(s)%2 != 0 {
+			return errOdd
+		}
+		return nil
+	})
+	defer pquads.RegisterLiteralValidator(typ, nil)
+	if err := pquads.ValidateLiteral(quad.TypedString{Value: "abc", Type: typ}); err != errOdd {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pquads.ValidateLiteral(quad.TypedString{Value: "ab", Type: typ}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
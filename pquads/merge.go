@@ -0,0 +1,84 @@
+package pquads
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cayleygraph/quad"
+)
+
+// DefaultMergeBlockQuads is the number of quads per block used by MergeIndexed if Options.BlockQuads is not set.
+const DefaultMergeBlockQuads = 1024
+
+// MergeIndexed merges quads from multiple sorted sources into a single sorted file without duplicates,
+// and returns the number of quads written.
+//
+// Each source must be sorted in canonical order (see CompareQuads), which sorts quads by subject first;
+// files written by ExternalSortWriter are sorted this way. Duplicates within and across sources are allowed and
+// only the first occurrence of each quad is written. If a source is out of order, an *OrderError is returned,
+// with the ordinal of the quad in that source. Sources are read with ReadQuad, thus they must not contain
+// tombstones, and they are not closed.
+//
+// The output is written with given options and always has a block index (see Options.BlockQuads).
+// DefaultMergeBlockQuads is used if the block size is not set. The block index also records the range
+// of subjects in the file (see IndexedReader.SubjectRange). Compressed output is allowed, but it cannot
+// be opened with NewIndexedReader.
+//
+// The merge keeps one quad from each source in memory, in addition to the writer state: an index entry
+// per block and, if Options.Dictionary is set, all distinct values since the last block start.
+func MergeIndexed(dst io.Writer, opts *Options, srcs ...*Reader) (int, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.BlockQuads <= 0 {
+		o.BlockQuads = DefaultMergeBlockQuads
+	}
+	ctx := context.Background()
+	h := make(mergeHeap, 0, len(srcs))
+	read := make([]int, len(srcs)) // quads read from each source
+	for i, r := range srcs {
+		it := &mergeItem{r: r, ind: i, cmp: CompareQuads}
+		if err := it.next(ctx); err == io.EOF {
+			continue
+		} else if err != nil {
+			return 0, fmt.Errorf("source %d: %w", i, err)
+		}
+		read[i]++
+		h = append(h, it)
+	}
+	heap.Init(&h)
+	w := NewWriter(dst, &o)
+	var (
+		n    int
+		prev quad.Quad // last quad written
+	)
+	for len(h) != 0 {
+		it := h[0]
+		q := it.q
+		if n == 0 || CompareQuads(prev, q) != 0 {
+			if err := w.WriteQuad(ctx, q); err != nil {
+				return n, err
+			}
+			if n == 0 {
+				w.first = q.Subject
+			}
+			w.last = q.Subject
+			prev = q
+			n++
+		}
+		if err := it.next(ctx); err == io.EOF {
+			heap.Pop(&h)
+		} else if err != nil {
+			return n, fmt.Errorf("source %d: %w", it.ind, err)
+		} else if CompareQuads(q, it.q) > 0 {
+			return n, &OrderError{Ordinal: read[it.ind], Prev: q, Quad: it.q}
+		} else {
+			read[it.ind]++
+			heap.Fix(&h, 0)
+		}
+	}
+	return n, w.Close()
+}
@@ -0,0 +1,84 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestMergeIndexed(t *testing.T) {
+	ctx := context.Background()
+	all := makeQuads(300)
+	sort.Sort(quad.ByQuadString(all))
+
+	// overlapping sources with duplicates
+	parts := [][]quad.Quad{all[:200], all[100:], append(append([]quad.Quad{}, all[50:60]...), all[50:60]...)}
+	sort.Sort(quad.ByQuadString(parts[2]))
+	open := func(opts *pquads.Options) []*pquads.Reader {
+		var srcs []*pquads.Reader
+		for _, p := range parts {
+			srcs = append(srcs, pquads.NewReader(bytes.NewReader(writeQuads(t, opts, p)), 0))
+		}
+		return srcs
+	}
+	for _, opts := range []*pquads.Options{
+		nil,
+		{BlockQuads: 50, Strict: true},
+		{BlockQuads: 50, Dictionary: true},
+	} {
+		buf := bytes.NewBuffer(nil)
+		n, err := pquads.MergeIndexed(buf, opts, open(opts)...)
+		if err != nil {
+			t.Fatal(err)
+		} else if n != len(all) {
+			t.Fatalf("expected %d quads, got %d", len(all), n)
+		}
+		data := buf.Bytes()
+		ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if ir.NumQuads() != len(all) {
+			t.Fatalf("unexpected number of quads in the index: %d", ir.NumQuads())
+		}
+		first, last, ok := ir.SubjectRange()
+		if !ok || first != all[0].Subject || last != all[len(all)-1].Subject {
+			t.Fatalf("unexpected subject range: %v, %v", first, last)
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(all, out) {
+			t.Fatal("corrupted quads")
+		}
+	}
+
+	// unsorted source
+	bad := append([]quad.Quad{}, all[:20]...)
+	bad[10], bad[11] = bad[11], bad[10]
+	_, err := pquads.MergeIndexed(bytes.NewBuffer(nil), nil,
+		pquads.NewReader(bytes.NewReader(writeQuads(t, nil, all[:20])), 0),
+		pquads.NewReader(bytes.NewReader(writeQuads(t, nil, bad)), 0),
+	)
+	var e *pquads.OrderError
+	if !errors.As(err, &e) || e.Ordinal != 11 {
+		t.Fatalf("expected order error, got: %v", err)
+	}
+
+	// empty sources
+	buf := bytes.NewBuffer(nil)
+	if n, err := pquads.MergeIndexed(buf, nil); err != nil || n != 0 {
+		t.Fatalf("unexpected result: %d, %v", n, err)
+	}
+	ir, err := pquads.NewIndexedReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()), 0)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, _, ok := ir.SubjectRange(); ok {
+		t.Fatal("unexpected subject range")
+	}
+}
@@ -0,0 +1,101 @@
+package pquads
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cayleygraph/quad"
+)
+
+// MonitorStats is a periodic report about the progress of a stream.
+type MonitorStats struct {
+	Quads       int64         // total number of quads read
+	Bytes       int64         // total number of bytes consumed from the source (compressed)
+	Elapsed     time.Duration // time since the monitor was started
+	QuadsPerSec float64       // read rate of quads during the last interval
+	BytesPerSec float64       // read rate of bytes during the last interval
+	// Stalled is set if no quads were read during the last interval.
+	Stalled bool
+}
+
+// defaultMonitorInterval is used by NewMonitoredReader for intervals that are not positive.
+const defaultMonitorInterval = time.Second
+
+var _ quad.ReadSkipCloser = (*MonitoredReader)(nil)
+
+// MonitoredReader is a quad reader that periodically reports stream statistics.
+type MonitoredReader struct {
+	r     *Reader
+	quads int64 // accessed atomically
+	stop  chan struct{}
+	done  sync.WaitGroup
+	once  sync.Once
+}
+
+// NewMonitoredReader wraps the reader and calls cb with stream statistics every interval, until the reader is closed.
+//
+// Counters are sampled by a separate goroutine, thus it adds only an atomic increment per quad to the read path.
+// The callback is called from that goroutine. If the interval is not positive, statistics are reported every second.
+func NewMonitoredReader(r *Reader, interval time.Duration, cb func(MonitorStats)) *MonitoredReader {
+	if interval <= 0 {
+		interval = defaultMonitorInterval
+	}
+	m := &MonitoredReader{r: r, stop: make(chan struct{})}
+	m.done.Add(1)
+	go m.run(interval, cb)
+	return m
+}
+
+func (m *MonitoredReader) run(interval time.Duration, cb func(MonitorStats)) {
+	defer m.done.Done()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	start := time.Now()
+	last, lastQuads, lastBytes := start, int64(0), int64(0)
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-t.C:
+			st := MonitorStats{
+				Quads:   atomic.LoadInt64(&m.quads),
+				Bytes:   m.r.src.Count(),
+				Elapsed: now.Sub(start),
+			}
+			if dt := now.Sub(last).Seconds(); dt > 0 {
+				st.QuadsPerSec = float64(st.Quads-lastQuads) / dt
+				st.BytesPerSec = float64(st.Bytes-lastBytes) / dt
+			}
+			st.Stalled = st.Quads == lastQuads
+			last, lastQuads, lastBytes = now, st.Quads, st.Bytes
+			cb(st)
+		}
+	}
+}
+
+func (m *MonitoredReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	q, err := m.r.ReadQuad(ctx)
+	if err == nil {
+		atomic.AddInt64(&m.quads, 1)
+	}
+	return q, err
+}
+
+func (m *MonitoredReader) SkipQuad(ctx context.Context) error {
+	err := m.r.SkipQuad(ctx)
+	if err == nil {
+		atomic.AddInt64(&m.quads, 1)
+	}
+	return err
+}
+
+// Close stops the monitor and closes the underlying reader. No callbacks are made after Close returns.
+func (m *MonitoredReader) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+		m.done.Wait()
+	})
+	return m.r.Close()
+}
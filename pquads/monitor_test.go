@@ -0,0 +1,84 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestMonitoredReader(t *testing.T) {
+	ctx := context.Background()
+	data := writeQuads(t, nil, makeQuads(100))
+	var (
+		mu    sync.Mutex
+		stats []pquads.MonitorStats
+	)
+	r := pquads.NewMonitoredReader(pquads.NewReader(bytes.NewReader(data), 0), 5*time.Millisecond, func(st pquads.MonitorStats) {
+		mu.Lock()
+		stats = append(stats, st)
+		mu.Unlock()
+	})
+	last := func() (pquads.MonitorStats, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(stats) == 0 {
+			return pquads.MonitorStats{}, 0
+		}
+		return stats[len(stats)-1], len(stats)
+	}
+	waitReport := func() pquads.MonitorStats {
+		_, n := last()
+		for i := 0; i < 1000; i++ {
+			time.Sleep(time.Millisecond)
+			if st, m := last(); m > n+1 {
+				return st
+			}
+		}
+		t.Fatal("no reports")
+		return pquads.MonitorStats{}
+	}
+	for i := 0; i < 50; i++ {
+		if _, err := r.ReadQuad(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := waitReport()
+	if st.Quads != 50 || st.Bytes != int64(len(data)) || !st.Stalled {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	for i := 0; i < 50; i++ {
+		if err := r.SkipQuad(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if st = waitReport(); st.Quads != 100 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, n := last()
+	time.Sleep(20 * time.Millisecond)
+	if _, m := last(); m != n {
+		t.Fatal("callback called after Close")
+	}
+}
+
+func TestMonitoredReaderInterval(t *testing.T) {
+	ctx := context.Background()
+	data := writeQuads(t, nil, makeQuads(10))
+	// not positive intervals fall back to the default one
+	for _, d := range []time.Duration{0, -time.Second} {
+		r := pquads.NewMonitoredReader(pquads.NewReader(bytes.NewReader(data), 0), d, func(pquads.MonitorStats) {})
+		if _, err := quad.ReadAll(ctx, r); err != nil {
+			t.Fatal(err)
+		} else if err = r.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package pquads
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const headerPaddingField = 11
+
+// padHeader appends padding fields to an encoded header that starts at offset off, so the header record
+// (including its length prefix) ends exactly at offset end.
+//
+// The size of the record doesn't grow evenly with the padding length, since length prefixes grow by a byte
+// at some lengths. Sizes that are skipped this way are reached with an additional empty padding field.
+func padHeader(data []byte, off, end int) ([]byte, error) {
+	for extra := 0; extra <= 1; extra++ {
+		for l := 0; off+l < end; l++ {
+			m := len(data) + 2*extra + protowire.SizeTag(headerPaddingField) + protowire.SizeBytes(l)
+			if off+protowire.SizeVarint(uint64(m))+m != end {
+				continue
+			}
+			for i := 0; i < extra; i++ {
+				data = protowire.AppendTag(data, headerPaddingField, protowire.BytesType)
+				data = protowire.AppendVarint(data, 0)
+			}
+			data = protowire.AppendTag(data, headerPaddingField, protowire.BytesType)
+			return protowire.AppendBytes(data, make([]byte, l)), nil
+		}
+	}
+	return nil, fmt.Errorf("header doesn't fit into %d bytes", end)
+}
@@ -0,0 +1,63 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestHeaderPad(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(10)
+	for _, opts := range []pquads.Options{
+		{},
+		{NoHeader: true},
+		{WriterVersion: true, BlockQuads: 3},
+	} {
+		for _, pad := range []int{24, 25, 130, 136, 138, 139, 140, 200, 4096, 16393, 16395, 20000} {
+			opts := opts
+			opts.HeaderPad = pad
+			data := writeQuads(t, &opts, in)
+			rr := pquads.NewRecordReader(bytes.NewReader(data), 0)
+			if opts.NoHeader {
+				rr = pquads.NewRecordReader(bytes.NewReader(append(writeQuads(t, nil, nil)[:8:8], data...)), 0)
+			}
+			var off int64
+			for {
+				rec, err := rr.ReadRecord(ctx)
+				if err != nil {
+					t.Fatal(err)
+				} else if rec.Type != pquads.RecordHeader {
+					off = rec.Offset
+					break
+				}
+			}
+			if opts.NoHeader {
+				off -= 8
+			}
+			if off != int64(pad) {
+				t.Fatalf("%+v: expected the first record at %d, got %d", opts, pad, off)
+			}
+			if opts.NoHeader {
+				continue
+			}
+			out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(in, out) {
+				t.Fatalf("%+v: corrupted quads", opts)
+			}
+		}
+	}
+	// the header doesn't fit, or the offset can't be reached
+	for _, pad := range []int{10, 137, 16394} {
+		w := pquads.NewWriter(bytes.NewBuffer(nil), &pquads.Options{HeaderPad: pad})
+		if err := w.WriteQuad(ctx, in[0]); err == nil {
+			t.Fatalf("expected an error for %d", pad)
+		}
+	}
+}
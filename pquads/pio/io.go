@@ -40,6 +40,21 @@ type Reader interface {
 	SkipMsg() error
 }
 
+// RawWriter is a Writer that can also write already encoded messages.
+type RawWriter interface {
+	Writer
+	// WriteRaw writes an encoded message with a length prefix.
+	WriteRaw(data []byte) (int, error)
+}
+
+// RawReader is a Reader that can also read messages without decoding them.
+type RawReader interface {
+	Reader
+	// ReadRaw reads the next message without decoding it.
+	// Returned slice is only valid until the next call to the reader.
+	ReadRaw() ([]byte, error)
+}
+
 type marshaler interface {
 	MarshalTo(data []byte) (n int, err error)
 }
@@ -106,3 +106,92 @@ func TestVarintError(t *testing.T) {
 		t.Fatalf("Expected error")
 	}
 }
+
+func TestVarintMaxSize(t *testing.T) {
+	const maxSize = 3
+	// encoded sizes are maxSize-1, maxSize and maxSize+1
+	msgs := []*test.TestMsg{{Value: 127}, {Value: 128}, {Value: 16384}}
+	for i, m := range msgs {
+		if n := m.SizeVT(); n != maxSize-1+i {
+			t.Fatalf("unexpected message size: %d", n)
+		}
+	}
+	for _, skip := range []bool{false, true} {
+		buf := bytes.NewBuffer(nil)
+		writer := io.NewWriter(buf)
+		for _, m := range msgs {
+			if _, err := writer.WriteMsg(m); err != nil {
+				t.Fatal(err)
+			}
+		}
+		reader := io.NewReader(buf, maxSize)
+		for i, m := range msgs {
+			var err error
+			if skip {
+				err = reader.SkipMsg()
+			} else {
+				msg := &test.TestMsg{}
+				if err = reader.ReadMsg(msg); err == nil && !msg.EqualVT(m) {
+					t.Fatalf("message %d is not equal to other message", i)
+				}
+			}
+			// messages larger than maxSize can still be skipped
+			if (i < len(msgs)-1 || skip) && err != nil {
+				t.Fatalf("message %d (skip=%v): unexpected error: %v", i, skip, err)
+			} else if i == len(msgs)-1 && !skip && err != goio.ErrShortBuffer {
+				t.Fatalf("message %d (skip=%v): expected size error, got: %v", i, skip, err)
+			}
+		}
+	}
+}
+
+var errMarshal = errors.New("marshal failed")
+
+// failMsg is a message that always fails to marshal.
+type failMsg struct {
+	*test.TestMsg
+}
+
+func (failMsg) Size() int                       { return 1 }
+func (failMsg) MarshalTo(p []byte) (int, error) { return 0, errMarshal }
+
+func TestVarintMarshalError(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	writer := io.NewWriter(buf)
+	msgs := []*test.TestMsg{{Value: 1}, {Value: 2}}
+	if _, err := writer.WriteMsg(msgs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteMsg(failMsg{&test.TestMsg{Value: 3}}); err != errMarshal {
+		t.Fatalf("expected marshal error, got: %v", err)
+	}
+	if _, err := writer.WriteMsg(msgs[1]); err != nil {
+		t.Fatal(err)
+	}
+	reader := io.NewReader(buf, 1024)
+	for i, m := range msgs {
+		msg := &test.TestMsg{}
+		if err := reader.ReadMsg(msg); err != nil {
+			t.Fatal(err)
+		} else if !msg.EqualVT(m) {
+			t.Fatalf("message %d is not equal to other message", i)
+		}
+	}
+	if err := reader.ReadMsg(&test.TestMsg{}); err != goio.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+}
+
+func TestVarintWriterReuse(t *testing.T) {
+	writer := io.NewWriter(goio.Discard)
+	msg := &test.TestMsg{Value: 1 << 20}
+	if _, err := writer.WriteMsg(msg); err != nil {
+		t.Fatal(err)
+	}
+	// the buffer of the first message is reused for the next ones
+	if n := testing.AllocsPerRun(10, func() {
+		writer.WriteMsg(msg)
+	}); n != 0 {
+		t.Fatalf("unexpected allocations per message: %v", n)
+	}
+}
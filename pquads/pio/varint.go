@@ -41,50 +41,73 @@ var (
 	errLargeValue  = errors.New("Value is Larger than 64 bits")
 )
 
+// NewWriter creates a writer for length-prefixed messages.
+//
+// Each message is fully marshaled before anything is written, and the length prefix is written together with
+// the message in a single Write call. Thus, a marshal error never leaves a partial frame in the output.
 func NewWriter(w io.Writer) Writer {
-	return &varintWriter{w: w, lenBuf: make([]byte, binary.MaxVarintLen64)}
+	return &varintWriter{w: w}
 }
 
 type varintWriter struct {
 	w      io.Writer
-	lenBuf []byte
 	buffer []byte
 }
 
-func (w *varintWriter) WriteMsg(msg proto.Message) (_ int, err error) {
-	var data []byte
+func (w *varintWriter) WriteMsg(msg proto.Message) (int, error) {
+	// reserve space for the length prefix, so it can be written in front of the message without copying it
+	const pref = binary.MaxVarintLen64
+	buf := w.buffer
+	if cap(buf) < pref {
+		buf = make([]byte, pref, 64)
+	}
+	buf = buf[:pref]
+	var err error
 	if m, ok := msg.(marshaler); ok {
-		n, ok := getSize(m)
-		if !ok {
-			data, err = proto.Marshal(msg)
-			if err != nil {
-				return 0, err
+		if n, ok := getSize(m); ok {
+			if cap(buf) < pref+n {
+				buf = make([]byte, pref, pref+n)
 			}
+			n, err = m.MarshalTo(buf[pref : pref+n])
+			buf = buf[:pref+n]
+		} else {
+			buf, err = proto.MarshalOptions{}.MarshalAppend(buf[:pref], msg)
 		}
-		if n >= len(w.buffer) {
-			w.buffer = make([]byte, n)
-		}
-		_, err = m.MarshalTo(w.buffer)
-		if err != nil {
-			return 0, err
-		}
-		data = w.buffer[:n]
 	} else {
-		data, err = proto.Marshal(msg)
-		if err != nil {
-			return 0, err
-		}
+		buf, err = proto.MarshalOptions{}.MarshalAppend(buf[:pref], msg)
 	}
-	length := uint64(len(data))
-	n := binary.PutUvarint(w.lenBuf, length)
-	n, err = w.w.Write(w.lenBuf[:n])
 	if err != nil {
-		return n, err
+		return 0, err
 	}
-	nd, err := w.w.Write(data)
-	return n + nd, err
+	w.buffer = buf
+	return w.writeFrame(buf, pref)
 }
 
+func (w *varintWriter) WriteRaw(data []byte) (int, error) {
+	const pref = binary.MaxVarintLen64
+	buf := w.buffer
+	if cap(buf) < pref+len(data) {
+		buf = make([]byte, pref, pref+len(data))
+	}
+	buf = append(buf[:pref], data...)
+	w.buffer = buf
+	return w.writeFrame(buf, pref)
+}
+
+// writeFrame writes a message from buf[pref:] with a length prefix that is placed right before it.
+func (w *varintWriter) writeFrame(buf []byte, pref int) (int, error) {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(buf)-pref))
+	start := pref - n
+	copy(buf[start:], lenBuf[:n])
+	return w.w.Write(buf[start:])
+}
+
+// NewReader creates a reader for length-prefixed messages.
+//
+// The maxSize argument is an inclusive limit for the encoded size of a single message, not counting the length prefix.
+// Messages larger than maxSize are rejected with io.ErrShortBuffer when reading them. SkipMsg doesn't buffer
+// messages, thus it skips messages of any size.
 func NewReader(r io.Reader, maxSize int) Reader {
 	return &varintReader{r: bufio.NewReader(r), maxSize: maxSize}
 }
@@ -126,11 +149,19 @@ func (r *varintReader) SkipMsg() error {
 }
 
 func (r *varintReader) ReadMsg(msg proto.Message) error {
-	if err := r.readLength(); err != nil {
+	buf, err := r.ReadRaw()
+	if err != nil {
 		return err
 	}
+	return proto.Unmarshal(buf, msg)
+}
+
+func (r *varintReader) ReadRaw() ([]byte, error) {
+	if err := r.readLength(); err != nil {
+		return nil, err
+	}
 	if r.len < 0 || r.len > r.maxSize {
-		return io.ErrShortBuffer
+		return nil, io.ErrShortBuffer
 	}
 	r.readLen = false
 	if len(r.buf) < r.len {
@@ -138,7 +169,7 @@ func (r *varintReader) ReadMsg(msg proto.Message) error {
 	}
 	buf := r.buf[:r.len]
 	if _, err := io.ReadFull(r.r, buf); err != nil {
-		return err
+		return nil, err
 	}
-	return proto.Unmarshal(buf, msg)
+	return buf, nil
 }
@@ -2,20 +2,37 @@
 package pquads
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
 
 	"github.com/cayleygraph/quad"
 	"github.com/cayleygraph/quad/pquads/pio"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 )
 
 var DefaultMaxSize = 1024 * 1024
 
-const currentVersion = 1
+// currentVersion is the version of the format written after the magic.
+//
+// Version 2 added records and values that readers of version 1 don't know about and would silently misread:
+// checkpoints, dictionary references, block indexes, footers, subject groups, sources, language tag references
+// and time deltas. Any stream may contain checkpoints (see Writer.Checkpoint), thus all streams are written
+// with version 2. Streams of version 1 have none of these and are still read.
+const (
+	currentVersion = 2
+	minVersion     = 1
+)
 
 var magic = [4]byte{0, 'p', 'q', 0}
 
@@ -34,12 +51,37 @@ func init() {
 }
 
 type Writer struct {
-	pw      pio.Writer
+	pw      pio.RawWriter
 	max     int
+	n       int // quads written
 	err     error
 	opts    Options
 	s, p, o quad.Value
 	cl      io.Closer
+	dict    map[quad.Value]uint64
+	shared  bool                  // dict is a shared dictionary, see DualOrderWriter
+	collect map[quad.Value]uint64 // collects written values for a shared dictionary, if not nil
+
+	zw   *gzip.Writer
+	zdst io.Writer // destination of the compressed stream
+	zn   int       // quads written to the current gzip member
+
+	off    int64               // bytes written (uncompressed)
+	blocks []*BlockIndex_Block // not nil if the block index is enabled
+	closed bool
+
+	crc *crcWriter // not nil if the rolling CRC is enabled
+
+	src srcState // see SetSource
+
+	dtypes map[string]quad.IRI // see Options.CanonicalDatatypes
+
+	first, last quad.Value // subject range stored in the block index, see MergeIndexed
+
+	langs map[string]uint64 // language tag dictionary, see Options.LangDictionary
+
+	lastTime time.Time // the previous timestamp, see Options.TimeDeltas
+	hasTime  bool
 }
 
 type Options struct {
@@ -49,33 +91,350 @@ type Options struct {
 	Full bool
 	// Strict can be set to only marshal quads allowed by RDF spec.
 	Strict bool
+	// CompactBooleans can be set to store xsd:boolean literals in canonical lexical form ("true" or "false")
+	// as a single boolean field instead of a full typed string. Such objects are read back as typed strings
+	// with the same lexical form and the full xsd:boolean datatype IRI. Native quad.Bool values are not affected.
+	//
+	// Literals in any other lexical form (like "1" or "TRUE") are stored as-is to preserve them exactly.
+	CompactBooleans bool
+	// NoHeader can be set to omit the file magic and version.
+	//
+	// The output is not a valid pquads file by itself, but a body fragment that can be assembled
+	// with other fragments into a single file with Concat.
+	NoHeader bool
+	// Dictionary can be set to deduplicate values using a dictionary shared by all directions.
+	//
+	// Each distinct value is written in full only once, and all subsequent occurrences in any direction are
+	// written as a reference to it. This reduces the size of files with repeated values, but both the encoder
+	// and decoder must keep all distinct values in memory (until the next checkpoint).
+	//
+	// The dictionary is not written as a separate section: a value is defined inline by the first quad that uses it,
+	// thus neither the writer nor the reader needs to buffer quads. Definitions are numbered from zero in the order
+	// they appear in the stream (in the order of directions within a quad, followed by the quad version),
+	// and a reference is the number of the definition, which always precedes it. Each checkpoint resets the numbering.
+	Dictionary bool
+	// WriterVersion can be set to record the version of this module in the header (see Reader.WriterVersion).
+	//
+	// It's not set by default to keep the output deterministic across versions.
+	WriterVersion bool
+	// PreserveInvalidUTF8 can be set to store strings that are not a valid UTF-8 as bytes, preserving them exactly.
+	//
+	// By default, such quads are rejected with ErrInvalidUTF8, since protobuf requires strings to be valid UTF-8.
+	// In Strict mode only objects can be preserved this way, and invalid IRIs and blank nodes are always rejected.
+	PreserveInvalidUTF8 bool
+	// Changelog can be set to allow writing delete and upsert operations with WriteChange.
+	Changelog bool
+	// BlockQuads can be set to split the stream into blocks of the given number of quads and to write a block index
+	// at the end of the file on Close. Such files can be read from any block with NewIndexedReader.
+	//
+	// Each block starts with a checkpoint, thus explicit checkpoints also start a new block.
+	BlockQuads int
+	// Compress can be set to compress the output with gzip. Such files are decompressed by NewReader automatically,
+	// but they are not seekable, thus cannot be used with NewIndexedReader or NewSubjectReader.
+	Compress bool
+	// CompressResetQuads can be set to restart the compression every given number of quads and at each checkpoint,
+	// by starting a new gzip member. The output is still a valid gzip stream, but each member is compressed
+	// independently from the previous data, thus a change in the input only affects a few members of the output.
+	// This makes binary diffs (e.g. by rsync) of compressed files small, at the cost of a slightly worse
+	// compression ratio.
+	//
+	// It has no effect if Compress is not set.
+	CompressResetQuads int
+	// QuadHashes can be set to store a hash of each quad (see quad.HashQuad), so readers can deduplicate quads
+	// across files without computing hashes. It adds 22 bytes per quad. See Reader.QuadHash.
+	QuadHashes bool
+	// SubjectGroups can be set to allow writing quads of a subject as a single record with WriteSubjectGroup.
+	SubjectGroups bool
+	// Sources can be set to allow recording the source of quads with Writer.SetSource.
+	Sources bool
+	// RollingCRC can be set to maintain a CRC of all bytes written to the stream and to store it in each checkpoint.
+	// A final checkpoint is written on Close, thus the whole file is covered by the checksum.
+	// Readers verify the checksums automatically. See Writer.RollingSum and NewAppendWriter.
+	RollingCRC bool
+	// CanonicalDatatypes can be set to a list of datatype IRIs to canonicalize (see DefaultCanonicalDatatypes).
+	//
+	// The datatype of each typed string in any direction is compared to this list ignoring the ASCII case,
+	// the http or https scheme and the prefixed form (like "xsd:int"). If it matches, it's replaced with the IRI
+	// from the list in its full form before encoding, thus "https://www.w3.org/2001/XMLSchema#INT" is stored
+	// as "http://www.w3.org/2001/XMLSchema#int" if the latter is in the list. Other datatypes are not changed.
+	//
+	// Canonicalization is applied before CompactBooleans and QuadHashes.
+	CanonicalDatatypes []quad.IRI
+	// HeaderPad can be set to pad the header, so the first record after it starts at the given offset
+	// from the start of the output (of the uncompressed stream, if Compress is set). Readers skip the padding.
+	//
+	// It must be larger than the header without padding, plus a few bytes for the padding field itself,
+	// otherwise the writer fails with an error. It also fails for the few offsets that are skipped when the length
+	// prefix of the header record grows by a byte: 137 and 16394 (129 and 16386 with NoHeader), and larger ones
+	// that are not practical.
+	HeaderPad int
+	// LangDictionary can be set to deduplicate language tags of language strings with a dictionary of tags.
+	//
+	// Like with Dictionary, a tag is written in full by the first language string that uses it, and subsequent
+	// strings with the same tag refer to it by number. It doesn't depend on repeated values, thus it also shrinks
+	// multilingual datasets where each label is distinct, and it can be combined with Dictionary.
+	// Each checkpoint resets the tag dictionary. Tags are compared exactly, and language strings are read back
+	// exactly as written.
+	LangDictionary bool
+	// LangTags can be set to a list of tags the language tag dictionary starts with, for example DefaultLangTags.
+	// They are written in the header once, thus language strings never write these tags in full.
+	//
+	// It has no effect if LangDictionary is not set.
+	LangTags []string
+	// TimeDeltas can be set to write timestamp values as a difference in nanoseconds from the previous timestamp
+	// in the stream. Timestamps of a dataset are often close to each other, for example versions of consecutive
+	// changes, thus a delta takes a few bytes instead of two full fields.
+	//
+	// Timestamps are read back with the same nanosecond precision, and a timestamp that is too far from
+	// the previous one to fit into a delta is written in full. As with full timestamps, the time zone is not stored:
+	// values are read in UTC. Each checkpoint resets the previous timestamp to TimeBase, thus blocks
+	// of an indexed file remain independent. With Dictionary, repeated timestamps still refer to the dictionary,
+	// and only the ones written in full are encoded as deltas.
+	TimeDeltas bool
+	// TimeBase is the time the first timestamp after the start of the stream or a checkpoint is relative to.
+	// If it's not set, such timestamps are written in full.
+	//
+	// It has no effect if TimeDeltas is not set.
+	TimeBase time.Time
+}
+
+// valueState reports whether values of a record may depend on values of preceding records, other than
+// the compaction of quad directions, thus records cannot be decoded without reading the preceding ones.
+func (o *Options) valueState() bool {
+	return o.Dictionary || o.LangDictionary || o.TimeDeltas
+}
+
+const modulePath = "github.com/cayleygraph/quad"
+
+// moduleVersion returns the version of this module from the build info, or "unknown".
+func moduleVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	mod := &bi.Main
+	for _, m := range bi.Deps {
+		if m.Path == modulePath {
+			mod = m
+			break
+		}
+	}
+	if mod.Path != modulePath || mod.Version == "" {
+		return "unknown"
+	}
+	if mod.Replace != nil && mod.Replace.Version != "" {
+		return mod.Replace.Version
+	}
+	return mod.Version
 }
 
 // NewWriter creates protobuf quads encoder.
 func NewWriter(w io.Writer, opts *Options) *Writer {
-	// Write file magic and version
-	buf := make([]byte, 8)
-	copy(buf[:4], magic[:])
-	binary.LittleEndian.PutUint32(buf[4:], currentVersion)
-	if _, err := w.Write(buf); err != nil {
-		return &Writer{err: err}
-	}
-	pw := pio.NewWriter(w)
+	return newWriter(w, opts, nil)
+}
+
+// newWriter creates an encoder. If shared is not nil, values are written as refs to this dictionary,
+// see Header.shared_dictionary. Options.Dictionary is ignored in this case.
+func newWriter(w io.Writer, opts *Options, shared map[quad.Value]uint64) *Writer {
 	if opts == nil {
 		opts = &Options{}
 	}
+	var (
+		zw   *gzip.Writer
+		zdst io.Writer
+	)
+	if opts.Compress {
+		zdst = w
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+	var cw *crcWriter
+	if opts.RollingCRC {
+		cw = &crcWriter{w: w}
+		w = cw
+	}
+	if !opts.NoHeader {
+		// Write file magic and version
+		buf := make([]byte, 8)
+		copy(buf[:4], magic[:])
+		binary.LittleEndian.PutUint32(buf[4:], currentVersion)
+		if _, err := w.Write(buf); err != nil {
+			return &Writer{err: err}
+		}
+	}
+	var off int64
+	if !opts.NoHeader {
+		off = int64(len(magic) + 4)
+	}
+	pw := pio.NewWriter(w).(pio.RawWriter)
 	// Write options header
-	_, err := pw.WriteMsg(&Header{
-		Full:      opts.Full,
-		NotStrict: !opts.Strict,
-	})
-	return &Writer{pw: pw, err: err, opts: *opts}
+	h := &Header{
+		Full:          opts.Full,
+		NotStrict:     !opts.Strict,
+		Dictionary:    opts.Dictionary,
+		Changelog:     opts.Changelog,
+		Hashes:        opts.QuadHashes,
+		RollingCrc:    opts.RollingCRC,
+		SubjectGroups: opts.SubjectGroups,
+		Sources:       opts.Sources,
+	}
+	var langTags []string
+	if opts.LangDictionary {
+		langTags = uniqueLangTags(opts.LangTags)
+		h.LangDictionary, h.LangTags = true, langTags
+	}
+	if opts.TimeDeltas {
+		h.TimeDeltas = true
+		if !opts.TimeBase.IsZero() {
+			h.TimeBase = makeTimestamp(opts.TimeBase)
+		}
+	}
+	if opts.WriterVersion {
+		h.WriterVersion = moduleVersion()
+	}
+	if shared != nil {
+		h.Dictionary = false
+		h.SharedDictionary, h.SharedValues = true, uint64(len(shared))
+	}
+	var (
+		n   int
+		err error
+	)
+	if opts.HeaderPad > 0 {
+		var data []byte
+		if data, err = h.MarshalVT(); err == nil {
+			data, err = padHeader(data, int(off), opts.HeaderPad)
+		}
+		if err != nil {
+			return &Writer{err: err}
+		}
+		n, err = pw.WriteRaw(data)
+	} else {
+		n, err = pw.WriteMsg(h)
+	}
+	qw := &Writer{pw: pw, err: err, opts: *opts, off: off + int64(n), zw: zw, zdst: zdst, crc: cw}
+	if shared != nil {
+		qw.opts.Dictionary = false
+		qw.dict, qw.shared = shared, true
+	} else if opts.Dictionary {
+		qw.dict = make(map[quad.Value]uint64)
+	}
+	qw.dtypes = newDatatypeMap(opts.CanonicalDatatypes)
+	qw.opts.LangTags = langTags
+	qw.resetLangs()
+	qw.resetTime()
+	if opts.BlockQuads > 0 {
+		qw.blocks = []*BlockIndex_Block{{Offset: uint64(qw.off)}}
+	}
+	return qw
 }
 func (w *Writer) WriteQuad(ctx context.Context, q quad.Quad) error {
+	return w.WriteChange(ctx, q, OpAdd)
+}
+
+// WriteChange writes a quad with a changelog operation. Operations other than OpAdd require Options.Changelog.
+func (w *Writer) WriteChange(ctx context.Context, q quad.Quad, op Op) error {
+	return w.writeChange(ctx, q, op, nil, 0)
+}
+
+// WriteQuadVersioned writes a quad with an additional version value, for example a revision of its graph.
+// The version is stored next to the quad and returned by Reader.QuadVersion. Nil version is the same as WriteQuad.
+//
+// Versions are not compacted, and are not covered by quad hashes (see Options.QuadHashes).
+func (w *Writer) WriteQuadVersioned(ctx context.Context, q quad.Quad, version quad.Value) error {
+	return w.writeChange(ctx, q, OpAdd, version, 0)
+}
+
+// WriteQuadFlags writes a quad with a bitmask of flags, which is returned by Reader.QuadFlags. The meaning of bits
+// is defined by the application, for example to tell asserted quads from the inferred ones. Zero flags are
+// the same as WriteQuad, and quads written without flags are read with zero flags.
+//
+// Flags take no space if they are zero, and a few bytes otherwise. Like versions, they are not covered
+// by quad hashes (see Options.QuadHashes).
+func (w *Writer) WriteQuadFlags(ctx context.Context, q quad.Quad, flags uint32) error {
+	return w.writeChange(ctx, q, OpAdd, nil, flags)
+}
+
+func (w *Writer) writeChange(ctx context.Context, q quad.Quad, op Op, version quad.Value, flags uint32) error {
 	if w.err != nil {
 		return w.err
+	}
+	invalidUTF8, err := w.checkChange(q, op, version, w.n)
+	if err != nil {
+		return err
+	} else if err = w.startRecord(); err != nil {
+		return err
+	}
+	m, err := w.encodeChange(q, op, version, flags, invalidUTF8)
+	if err != nil {
+		return err
+	}
+	return w.writeRecord(m, 1)
+}
+
+// checkChange validates a quad with its operation and version. Ordinal is only used for error messages.
+// It reports if the quad has strings that are not a valid UTF-8, but should be preserved.
+func (w *Writer) checkChange(q quad.Quad, op Op, version quad.Value, ordinal int) (invalidUTF8 bool, _ error) {
+	if op != OpAdd && !w.opts.Changelog {
+		return false, fmt.Errorf("%v operation requires a changelog", op)
+	} else if op < OpAdd || op > OpUpsert {
+		return false, fmt.Errorf("invalid operation: %v", op)
 	} else if !q.IsValid() {
-		return quad.ErrInvalid
+		return false, quad.ErrInvalid
+	}
+	for _, d := range quad.Directions {
+		if validUTF8(q.Get(d)) {
+			continue
+		}
+		invalidUTF8 = true
+		if !w.opts.PreserveInvalidUTF8 || (w.opts.Strict && d != quad.Object) {
+			return false, fmt.Errorf("%w in %v of quad %d: %v", ErrInvalidUTF8, d, ordinal, q)
+		}
+	}
+	if !validUTF8(version) {
+		return false, fmt.Errorf("%w in version of quad %d: %v", ErrInvalidUTF8, ordinal, version)
+	}
+	return invalidUTF8, nil
+}
+
+// startRecord starts a new block or gzip member before the next record, if it's time to do so.
+func (w *Writer) startRecord() error {
+	if w.blocks != nil && w.n-int(w.blocks[len(w.blocks)-1].Ordinal) >= w.opts.BlockQuads {
+		if err := w.Checkpoint(); err != nil {
+			return err
+		}
+	}
+	if w.zw != nil && w.opts.CompressResetQuads > 0 && w.zn >= w.opts.CompressResetQuads {
+		if err := w.resetGzip(); err != nil {
+			return err
+		}
+	}
+	return w.writeSource()
+}
+
+// encodeChange converts a validated quad to a record, updating the compaction and dictionary state.
+func (w *Writer) encodeChange(q quad.Quad, op Op, version quad.Value, flags uint32, invalidUTF8 bool) (proto.Message, error) {
+	if w.dtypes != nil {
+		for _, d := range quad.Directions {
+			q.Set(d, canonicalDatatype(w.dtypes, q.Get(d)))
+		}
+	}
+	if w.opts.CompactBooleans {
+		q.Object = compactBool(q.Object)
+	}
+	if w.collect != nil {
+		for _, d := range quad.Directions {
+			if v := q.Get(d); v != nil {
+				k := sharedKey(v)
+				if _, ok := w.collect[k]; !ok {
+					w.collect[k] = uint64(len(w.collect))
+				}
+			}
+		}
+	}
+	var hash []byte
+	if w.opts.QuadHashes {
+		hash = quad.HashQuad(q)
 	}
 	if !w.opts.Full {
 		if q.Subject == w.s {
@@ -95,19 +454,116 @@ func (w *Writer) WriteQuad(ctx context.Context, q quad.Quad) error {
 		}
 	}
 	var m proto.Message
-	if w.opts.Strict {
+	if w.dict != nil {
+		m, w.err = w.makeDictQuad(q)
+		if w.err != nil {
+			return nil, w.err
+		}
+	} else if w.opts.Strict {
 		m, w.err = makeStrictQuad(q)
 		if w.err != nil {
-			return w.err
+			return nil, w.err
 		}
 	} else {
 		m = makeWireQuad(q)
 	}
+	if invalidUTF8 {
+		preserveUTF8(m, q)
+	}
+	if w.err = encodeValues(m, q); w.err != nil {
+		return nil, w.err
+	}
+	if w.opts.CompactBooleans {
+		compactBools(m)
+	}
+	var ver *Value
+	if w.dict != nil {
+		ver = w.dictValue(version)
+	} else {
+		ver = MakeValue(version)
+	}
+	switch m := m.(type) {
+	case *WireQuad:
+		m.Op, m.Hash, m.Version, m.Flags = uint32(op), hash, ver, flags
+	case *StrictQuad:
+		m.Op, m.Hash, m.Version, m.Flags = uint32(op), hash, ver, flags
+	}
+	return m, nil
+}
+
+// writeRecord writes a record containing a given number of quads.
+func (w *Writer) writeRecord(m proto.Message, quads int) error {
+	if w.langs != nil {
+		langStrings(m, w.encodeLang)
+	}
+	if w.opts.TimeDeltas {
+		recordValues(m, w.encodeTime)
+	}
 	var n int
 	n, w.err = w.pw.WriteMsg(m)
+	w.off += int64(n)
 	if n > w.max {
 		w.max = n
 	}
+	if w.err == nil {
+		w.n += quads
+		w.zn += quads
+	}
+	return w.err
+}
+
+// resetGzip finishes the current gzip member and starts a new one.
+func (w *Writer) resetGzip() error {
+	if w.err = w.zw.Close(); w.err != nil {
+		return w.err
+	}
+	w.zw.Reset(w.zdst)
+	w.zn = 0
+	return nil
+}
+
+// Checkpoint writes a checkpoint record carrying the number of quads written so far.
+//
+// Values are not carried over across the checkpoint, thus the stream can be decoded starting from
+// the next record (given the options from the header). See Reader.LastCheckpoint.
+func (w *Writer) Checkpoint() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.zw != nil && w.opts.CompressResetQuads > 0 && w.zn > 0 {
+		if err := w.resetGzip(); err != nil {
+			return err
+		}
+	}
+	cp := &Checkpoint{Ordinal: uint64(w.n)}
+	if w.crc != nil {
+		cp.Crc = w.crc.sum
+	}
+	var m proto.Message
+	if w.opts.Strict {
+		m = &StrictQuad{Checkpoint: cp}
+	} else {
+		m = &WireQuad{Checkpoint: cp}
+	}
+	if w.blocks != nil {
+		b := &BlockIndex_Block{Offset: uint64(w.off), Ordinal: uint64(w.n)}
+		if last := len(w.blocks) - 1; w.blocks[last].Ordinal == b.Ordinal {
+			// the previous block is empty
+			w.blocks[last] = b
+		} else {
+			w.blocks = append(w.blocks, b)
+		}
+	}
+	var n int
+	n, w.err = w.pw.WriteMsg(m)
+	w.off += int64(n)
+	w.s, w.p, w.o = nil, nil, nil
+	if w.dict != nil && !w.shared {
+		w.dict = make(map[quad.Value]uint64)
+	}
+	w.resetLangs()
+	w.resetTime()
+	w.src.reset()
 	return w.err
 }
 
@@ -120,26 +576,123 @@ func (w *Writer) WriteQuads(ctx context.Context, buf []quad.Quad) (int, error) {
 	return len(buf), nil
 }
 
-// MaxSize returns a maximal message size written.
+// MaxSize returns a maximal message size written, including the length prefix.
+//
+// It can be passed to NewReader to read the file back with the smallest buffer.
 func (w *Writer) MaxSize() int {
 	return w.max
 }
 func (w *Writer) SetCloser(c io.Closer) {
 	w.cl = c
 }
+
+// Close writes the block index (if enabled), flushes the compressed stream and closes the underlying writer
+// set with SetCloser.
 func (w *Writer) Close() error {
+	var err error
+	if w.crc != nil && !w.closed {
+		err = w.finalCheckpoint()
+	}
+	if w.blocks != nil && !w.closed && err == nil {
+		err = w.writeIndex()
+	}
+	if w.zw != nil && !w.closed {
+		if err2 := w.zw.Close(); err == nil {
+			err = err2
+		}
+	}
+	w.closed = true
 	if w.cl != nil {
-		return w.cl.Close()
+		if err2 := w.cl.Close(); err == nil {
+			err = err2
+		}
 	}
-	return nil
+	return err
 }
 
 type Reader struct {
-	pr      pio.Reader
+	pr      pio.RawReader
 	err     error
 	opts    Options
 	s, p, o quad.Value
 	cl      io.Closer
+
+	src   *countingReader // source stream (compressed, if zr is set)
+	zr    *gzip.Reader
+	guard *bombGuard
+	n     int // quads read or skipped
+
+	cp    int // ordinal of the last checkpoint
+	hasCp bool
+
+	dict   []quad.Value
+	shared bool // dict is a shared dictionary, see NewCompanionReader
+
+	version string // version of the writer
+	hsize   int    // size of the header record
+
+	wgroup []*WireQuad   // remaining quads of the current subject group
+	sgroup []*StrictQuad // same as wgroup, for strict files
+
+	hash   []byte     // hash of the last quad
+	qver   quad.Value // version of the last quad
+	flags  uint32     // flags of the last quad
+	verify bool       // verify quad hashes
+
+	footer   bool  // the footer was read
+	trailing error // set if data follows the footer
+
+	srcName  string   // source of the last quad
+	srcNames []string // names of source ids, starting from 1
+
+	crc    bool   // verify the rolling CRC
+	sum    uint32 // rolling CRC of records read so far
+	cpSum  uint32 // rolling CRC preceding the last record
+	lastCp bool   // the last record was a checkpoint
+
+	alloc Allocator // see SetAllocator
+	pool  ValuePool // see SetValuePool
+
+	next []byte // a record that was read by readRaw, but not decoded yet
+
+	langs []string // language tag dictionary, see Options.LangDictionary
+
+	lastTime time.Time // the previous timestamp, see Options.TimeDeltas
+	hasTime  bool
+
+	// state preceding the last decoded record, see Cursor
+	recLangs   int
+	recTime    time.Time
+	recHasTime bool
+
+	off     int64  // offset of the next record in the uncompressed stream
+	last    []byte // the last record read by readRaw, valid until the next read
+	hsum    uint32 // CRC-32C of the magic, version and header record
+	maxSize int
+}
+
+// countingReader counts bytes read from the underlying reader. The counter can be read concurrently.
+type countingReader struct {
+	r  io.Reader
+	n  int64
+	tm *PhaseTimings // see Reader.RecordTimings
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	if r.tm == nil {
+		n, err := r.r.Read(p)
+		atomic.AddInt64(&r.n, int64(n))
+		return n, err
+	}
+	start := time.Now()
+	n, err := r.r.Read(p)
+	r.tm.IO.add(time.Since(start))
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+func (r *countingReader) Count() int64 {
+	return atomic.LoadInt64(&r.n)
 }
 
 func (r *Reader) SetCloser(c io.Closer) {
@@ -151,54 +704,300 @@ var _ quad.Skipper = (*Reader)(nil)
 // NewReader creates protobuf quads decoder.
 //
 // MaxSize argument limits maximal size of the buffer used to read quads.
+// The limit is inclusive: a quad encoded in exactly maxSize bytes is accepted.
+//
+// Gzip-compressed streams are detected and decompressed automatically.
 func NewReader(r io.Reader, maxSize int) *Reader {
+	qr, h := openReader(r, maxSize)
+	if qr.err == nil && h.Ids {
+		qr.err = fmt.Errorf("pquads file contains id-based quads, use NewIDReader")
+	} else if qr.err == nil && h.SharedDictionary {
+		qr.err = fmt.Errorf("pquads file refers to a shared dictionary, use NewCompanionReader")
+	}
+	return qr
+}
+
+// openReader reads magic, version and header of the file.
+func openReader(r io.Reader, maxSize int) (*Reader, *Header) {
 	if maxSize <= 0 {
 		maxSize = DefaultMaxSize
 	}
-	qr := &Reader{}
+	qr := &Reader{src: &countingReader{r: r}, maxSize: maxSize}
+	br := bufio.NewReader(qr.src)
+	r = br
+	if pref, _ := br.Peek(2); len(pref) == 2 && pref[0] == 0x1f && pref[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			qr.err = err
+			return qr, nil
+		}
+		qr.zr = zr
+		qr.guard = &bombGuard{
+			r: zr, src: qr.src,
+			maxRatio: DefaultMaxExpansionRatio,
+			maxSize:  DefaultMaxDecompressedSize,
+		}
+		r = qr.guard
+	}
 	buf := make([]byte, 8)
 	if _, err := io.ReadFull(r, buf); err != nil {
 		qr.err = err
-		return qr
+		return qr, nil
 	} else if bytes.Compare(magic[:], buf[:4]) != 0 {
 		qr.err = fmt.Errorf("not a pquads file")
-		return qr
+		return qr, nil
 	}
 	vers := binary.LittleEndian.Uint32(buf[4:])
-	if vers != currentVersion {
+	if vers < minVersion || vers > currentVersion {
 		qr.err = fmt.Errorf("unsupported pquads version: %d", vers)
-		return qr
+		return qr, nil
 	}
 
-	qr.pr = pio.NewReader(r, maxSize)
-	var h Header
-	if err := qr.pr.ReadMsg(&h); err != nil {
+	qr.pr = pio.NewReader(r, maxSize).(pio.RawReader)
+	h := &Header{}
+	data, err := qr.pr.ReadRaw()
+	if err == nil {
+		qr.hsize = protowire.SizeBytes(len(data))
+		qr.off = int64(len(buf) + qr.hsize)
+		qr.hsum = headerSum(buf, data)
+		err = proto.Unmarshal(data, h)
+	}
+	if err != nil {
 		qr.err = err
+		return qr, h
+	}
+	if h.RollingCrc {
+		qr.crc = true
+		qr.sum = crc32.Update(0, crcTable, buf)
+		qr.updateSum(data)
 	}
 	qr.opts = Options{
-		Full:   h.Full,
-		Strict: !h.NotStrict,
+		Full:          h.Full,
+		Strict:        !h.NotStrict,
+		Dictionary:    h.Dictionary,
+		Changelog:     h.Changelog,
+		QuadHashes:    h.Hashes,
+		RollingCRC:    h.RollingCrc,
+		SubjectGroups: h.SubjectGroups,
+		Sources:       h.Sources,
 	}
-	return qr
+	if h.LangDictionary {
+		qr.opts.LangDictionary, qr.opts.LangTags = true, h.LangTags
+		qr.resetLangs()
+	}
+	if h.TimeDeltas {
+		qr.opts.TimeDeltas, qr.opts.TimeBase = true, timeBase(h.TimeBase)
+		qr.resetTime()
+	}
+	if h.SharedDictionary {
+		// decoded like a dictionary, but the dictionary is never extended or reset
+		qr.opts.Dictionary, qr.shared = true, true
+	}
+	qr.version = h.WriterVersion
+	return qr, h
 }
-func (r *Reader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+
+// NewSectionReader creates protobuf quads decoder for a pquads stream embedded into a larger file.
+//
+// The stream must start at offset off and span exactly n bytes. The reader returns io.EOF at the end of the
+// section, regardless of the data that follows it. An error is returned if the section has no valid header.
+func NewSectionReader(ra io.ReaderAt, off, n int64, maxSize int) (*Reader, error) {
+	r := NewReader(io.NewSectionReader(ra, off, n), maxSize)
 	if r.err != nil {
+		return nil, r.err
+	}
+	return r, nil
+}
+
+// ReadQuad reads the next quad.
+//
+// If the file is a changelog (see Options.Changelog), upserts are returned as regular quads, but ReadQuad
+// fails with ErrTombstone on the first delete, since the result would not be a valid set of quads.
+// Use ReadChange to read such files.
+func (r *Reader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	q, op, err := r.ReadChange(ctx)
+	if err == nil && op == OpDelete {
+		r.err = ErrTombstone
 		return quad.Quad{}, r.err
 	}
-	var q quad.Quad
-	if r.opts.Strict {
-		var pq StrictQuad
-		if r.err = r.pr.ReadMsg(&pq); r.err != nil {
-			return quad.Quad{}, r.err
+	return q, err
+}
+
+// ReadChange reads the next quad with its changelog operation. Files that are not changelogs only contain adds.
+func (r *Reader) ReadChange(ctx context.Context) (quad.Quad, Op, error) {
+	for {
+		if r.err != nil {
+			return quad.Quad{}, 0, r.err
 		}
-		q = pq.ToNative()
-	} else {
-		var pq WireQuad
-		if r.err = r.pr.ReadMsg(&pq); r.err != nil {
-			return quad.Quad{}, r.err
+		var (
+			q     quad.Quad
+			op    uint32
+			cp    *Checkpoint
+			hash  []byte
+			ver   *Value
+			flags uint32
+		)
+		var (
+			start time.Time
+			data  []byte
+			fast  bool // the quad was decoded by decodeQuadFast
+		)
+		if len(r.sgroup) == 0 && len(r.wgroup) == 0 {
+			if r.next != nil {
+				data, r.next = r.next, nil
+			} else if data, r.err = r.readRaw(); r.err != nil {
+				return quad.Quad{}, 0, r.err
+			}
+			q, fast = r.decodeFast(data)
+		}
+		if fast {
+			// the record has nothing but quad values
+		} else if r.opts.Strict {
+			var pq *StrictQuad
+			if len(r.sgroup) != 0 {
+				pq, r.sgroup = r.sgroup[0], r.sgroup[1:]
+			} else {
+				pq = &StrictQuad{}
+				if r.err = r.unmarshal(data, pq); r.err == nil {
+					r.err = r.decodeValues(pq)
+				}
+				if r.err != nil {
+					return quad.Quad{}, 0, r.err
+				} else if pq.Footer != nil {
+					r.endAfterFooter()
+					continue
+				} else if pq.Index != nil {
+					continue
+				} else if len(pq.Group) != 0 {
+					r.err = r.startStrictGroup(pq)
+					continue
+				} else if pq.Source != nil {
+					r.err = r.setSource(pq.Source)
+					continue
+				}
+			}
+			if r.src.tm != nil {
+				start = time.Now()
+			}
+			if cp = pq.Checkpoint; cp == nil && r.opts.Dictionary {
+				q, r.err = r.dictStrictQuad(pq)
+			} else {
+				q = r.strictQuad(pq)
+			}
+			op, hash, ver, flags = pq.Op, pq.Hash, pq.Version, pq.Flags
+		} else {
+			var pq *WireQuad
+			if len(r.wgroup) != 0 {
+				pq, r.wgroup = r.wgroup[0], r.wgroup[1:]
+			} else {
+				pq = &WireQuad{}
+				if r.err = r.unmarshal(data, pq); r.err == nil {
+					r.err = r.decodeValues(pq)
+				}
+				if r.err != nil {
+					return quad.Quad{}, 0, r.err
+				} else if pq.Footer != nil {
+					r.endAfterFooter()
+					continue
+				} else if pq.Index != nil {
+					continue
+				} else if len(pq.Group) != 0 {
+					r.err = r.startWireGroup(pq)
+					continue
+				} else if pq.Source != nil {
+					r.err = r.setSource(pq.Source)
+					continue
+				}
+			}
+			if r.src.tm != nil {
+				start = time.Now()
+			}
+			if cp = pq.Checkpoint; cp == nil && r.opts.Dictionary {
+				q, r.err = r.dictWireQuad(pq)
+			} else {
+				q = r.wireQuad(pq)
+			}
+			op, hash, ver, flags = pq.Op, pq.Hash, pq.Version, pq.Flags
+		}
+		if r.err != nil {
+			return quad.Quad{}, 0, r.err
+		}
+		if cp != nil {
+			r.checkpoint(cp)
+			continue
+		}
+		r.lastCp = false
+		if Op(op) > OpUpsert || (op != 0 && !r.opts.Changelog) {
+			r.err = fmt.Errorf("invalid operation for quad %d: %d", r.n, op)
+			return quad.Quad{}, 0, r.err
+		}
+		if r.opts.Dictionary {
+			if r.qver, r.err = r.dictValue(ver); r.err != nil {
+				return quad.Quad{}, 0, r.err
+			}
+		} else {
+			r.qver = r.value(ver)
 		}
-		q = pq.ToNative()
+		q = r.fillQuad(q)
+		r.hash, r.flags = hash, flags
+		if r.verify && r.opts.QuadHashes && !bytes.Equal(hash, quad.HashQuad(q)) {
+			r.err = fmt.Errorf("quad %d: %w", r.n-1, ErrHashMismatch)
+			return quad.Quad{}, 0, r.err
+		}
+		if tm := r.src.tm; tm != nil {
+			tm.Convert.add(time.Since(start))
+		}
+		return q, Op(op), nil
 	}
+}
+
+// ErrHashMismatch is returned if a quad doesn't match its stored hash. See Reader.VerifyHashes.
+var ErrHashMismatch = errors.New("quad hash mismatch")
+
+// QuadHash returns the hash of the last quad read, as stored with Options.QuadHashes.
+// It returns nil if the file doesn't contain hashes. The hash is computed by quad.HashQuad.
+func (r *Reader) QuadHash() []byte {
+	return r.hash
+}
+
+// QuadVersion returns the version of the last quad read, as written by Writer.WriteQuadVersioned.
+// It returns nil if the quad has no version.
+func (r *Reader) QuadVersion() quad.Value {
+	return r.qver
+}
+
+// ReadQuadVersioned is the same as ReadQuad, but also returns the version of the quad. See QuadVersion.
+func (r *Reader) ReadQuadVersioned(ctx context.Context) (quad.Quad, quad.Value, error) {
+	q, err := r.ReadQuad(ctx)
+	if err != nil {
+		return quad.Quad{}, nil, err
+	}
+	return q, r.qver, nil
+}
+
+// QuadFlags returns the flags of the last quad read, as written by Writer.WriteQuadFlags.
+// It returns zero if the quad has no flags.
+func (r *Reader) QuadFlags() uint32 {
+	return r.flags
+}
+
+// ReadQuadFlags is the same as ReadQuad, but also returns the flags of the quad. See QuadFlags.
+func (r *Reader) ReadQuadFlags(ctx context.Context) (quad.Quad, uint32, error) {
+	q, err := r.ReadQuad(ctx)
+	if err != nil {
+		return quad.Quad{}, 0, err
+	}
+	return q, r.flags, nil
+}
+
+// VerifyHashes enables or disables verification of stored quad hashes. If enabled, the reader recomputes
+// the hash of each quad and fails with ErrHashMismatch if it's different from the stored one.
+func (r *Reader) VerifyHashes(on bool) {
+	r.verify = on
+}
+
+// fillQuad sets directions omitted by the compaction to values from the previous quad.
+func (r *Reader) fillQuad(q quad.Quad) quad.Quad {
 	if q.Subject == nil {
 		q.Subject = r.s
 	} else {
@@ -214,20 +1013,183 @@ func (r *Reader) ReadQuad(ctx context.Context) (quad.Quad, error) {
 	} else {
 		r.o = q.Object
 	}
-	return q, nil
+	r.n++
+	return q
+}
+
+func (r *Reader) checkpoint(cp *Checkpoint) {
+	if r.crc && cp.Crc != r.cpSum {
+		r.err = fmt.Errorf("checkpoint at quad %d: %w", cp.Ordinal, ErrChecksum)
+		return
+	}
+	r.lastCp = true
+	r.srcName, r.srcNames = "", r.srcNames[:0]
+	r.s, r.p, r.o = nil, nil, nil
+	r.cp, r.hasCp = int(cp.Ordinal), true
+	if !r.shared {
+		r.dict = r.dict[:0]
+	}
+	r.resetLangs()
+	r.resetTime()
 }
+
 func (r *Reader) SkipQuad(ctx context.Context) error {
-	if !r.opts.Full {
+	if !r.opts.Full || r.opts.valueState() || r.opts.SubjectGroups {
 		// TODO(dennwc): read pb fields as bytes and unmarshal them only if ReadQuad is called
-		_, err := r.ReadQuad(ctx)
+		_, _, err := r.ReadChange(ctx)
 		return err
 	}
-	r.err = r.pr.SkipMsg()
+	for {
+		if r.err != nil {
+			return r.err
+		}
+		var data []byte
+		if data, r.err = r.readRaw(); r.err != nil {
+			return r.err
+		}
+		if !r.skipNonQuad(data) {
+			r.lastCp = false
+			r.n++
+			return nil
+		}
+	}
+}
+
+// skipNonQuad handles a record that is not a quad: a checkpoint, a source, the block index or the footer.
+// It reports false for quad records, which are left for the caller.
+func (r *Reader) skipNonQuad(data []byte) bool {
+	switch {
+	case hasField(data, footerField):
+		r.endAfterFooter()
+	case hasField(data, indexField):
+	case hasField(data, sourceField):
+		var pq WireQuad
+		if r.err = pq.UnmarshalVT(data); r.err == nil {
+			r.err = r.setSource(pq.Source)
+		}
+	case hasField(data, checkpointField):
+		// checkpoint records have no other fields, thus it's cheap to decode them
+		var pq WireQuad
+		if r.err = pq.UnmarshalVT(data); r.err == nil {
+			r.checkpoint(pq.Checkpoint)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// ErrTrailingData is returned by Reader.VerifyEnd if the stream has data after the last record.
+var ErrTrailingData = errors.New("trailing data after the end of pquads stream")
+
+// endAfterFooter stops reading after the footer record, which is always the last one.
+// Anything that follows it is recorded as trailing data and reported by VerifyEnd.
+func (r *Reader) endAfterFooter() {
+	r.err, r.footer = io.EOF, true
+	if _, err := r.pr.ReadRaw(); err != io.EOF {
+		r.trailing = fmt.Errorf("%w: data follows the footer", ErrTrailingData)
+	}
+}
+
+// VerifyEnd checks that the stream ends exactly after the last record. It must be called after ReadQuad
+// returned io.EOF, otherwise it returns an error: either the one the reading stopped with, or a new one
+// if the stream wasn't read to the end yet.
+//
+// The reader always consumes the source until io.EOF, thus for most files trailing data would fail
+// the decoding of the next record before VerifyEnd is called. The check is meaningful in two cases:
+// files with a block index (see Options.BlockQuads), where data after the footer record is ignored
+// by ReadQuad, and compressed streams, where garbage after the last gzip member is reported
+// as ErrTrailingData instead of a gzip header error.
+//
+// In all other cases VerifyEnd returns nil, since reaching io.EOF means that the source is exhausted.
+// Note that for NewSectionReader the end of the stream is the end of the section.
+func (r *Reader) VerifyEnd() error {
+	switch {
+	case r.err == nil:
+		return errors.New("pquads stream was not read to the end")
+	case r.err == io.EOF:
+		return r.trailing
+	case r.zr != nil && errors.Is(r.err, gzip.ErrHeader):
+		// the first header is checked when opening the reader, thus it can only fail between members
+		return fmt.Errorf("%w: %v", ErrTrailingData, r.err)
+	}
 	return r.err
 }
+
+// LastCheckpoint returns the ordinal of the most recent checkpoint record read from the stream,
+// which is the number of quads written before the checkpoint.
+// It returns false if no checkpoints were read yet.
+func (r *Reader) LastCheckpoint() (ordinal int, ok bool) {
+	return r.cp, r.hasCp
+}
+
+// WriterVersion returns the version of the module that wrote the file, as recorded with Options.WriterVersion.
+// It returns an empty string if the version was not recorded.
+func (r *Reader) WriterVersion() string {
+	return r.version
+}
+
+// Progress returns the number of bytes consumed from the source stream and the number of quads read so far.
+//
+// For compressed streams the byte count is measured before decompression, thus it can be compared
+// directly with the size of the input file, even if the decompressed size is unknown.
+// It may run ahead of the last quad by the size of the internal read buffer.
+func (r *Reader) Progress() (compressedBytesRead int64, quadsRead int) {
+	return r.src.Count(), r.n
+}
+
 func (r *Reader) Close() error {
+	if r.zr != nil {
+		r.zr.Close()
+	}
 	if r.cl != nil {
 		return r.cl.Close()
 	}
 	return nil
 }
+
+// Numbers of fields for non-quad records, same for both WireQuad and StrictQuad.
+const (
+	checkpointField = 5
+	indexField      = 7
+	footerField     = 8
+	groupField      = 11
+	sourceField     = 12
+)
+
+// countField returns the number of top-level fields with a given number in an encoded message.
+func countField(data []byte, num protowire.Number) int {
+	cnt := 0
+	for len(data) > 0 {
+		n, typ, l := protowire.ConsumeTag(data)
+		if l < 0 {
+			return cnt
+		} else if n == num {
+			cnt++
+		}
+		data = data[l:]
+		if l = protowire.ConsumeFieldValue(n, typ, data); l < 0 {
+			return cnt
+		}
+		data = data[l:]
+	}
+	return cnt
+}
+
+// hasField checks if an encoded message has a top-level field with a given number.
+func hasField(data []byte, num protowire.Number) bool {
+	for len(data) > 0 {
+		n, typ, l := protowire.ConsumeTag(data)
+		if l < 0 {
+			return false
+		} else if n == num {
+			return true
+		}
+		data = data[l:]
+		if l = protowire.ConsumeFieldValue(n, typ, data); l < 0 {
+			return false
+		}
+		data = data[l:]
+	}
+	return false
+}
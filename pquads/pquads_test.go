@@ -2,12 +2,18 @@ package pquads_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cayleygraph/quad"
 	"github.com/cayleygraph/quad/pquads"
+	"github.com/cayleygraph/quad/pquads/pio"
 )
 
 var testData = []struct {
@@ -62,6 +68,8 @@ func TestPQuads(t *testing.T) {
 		{Full: false, Strict: true},
 		{Full: true, Strict: false},
 		{Full: true, Strict: true},
+		{Full: false, Strict: false, Dictionary: true},
+		{Full: true, Strict: true, Dictionary: true},
 	} {
 		name := ""
 		if opts.Full {
@@ -72,6 +80,9 @@ func TestPQuads(t *testing.T) {
 		if opts.Strict {
 			name += " strict"
 		}
+		if opts.Dictionary {
+			name += " dict"
+		}
 		t.Run(name, func(t *testing.T) {
 			ctx := context.Background()
 			for _, c := range testData {
@@ -100,3 +111,575 @@ func TestPQuads(t *testing.T) {
 		})
 	}
 }
+
+func TestCompactBooleans(t *testing.T) {
+	const boolType = quad.IRI("http://www.w3.org/2001/XMLSchema#boolean")
+	in := []quad.Quad{
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+		quad.MakeIRI("a", "b", "c", ""),
+	}
+	in[0].Object = quad.TypedString{Value: "true", Type: boolType}
+	in[1].Object = quad.TypedString{Value: "false", Type: "xsd:boolean"}
+	in[2].Object = quad.TypedString{Value: "1", Type: boolType}
+	in[3].Object = quad.Bool(true)
+	exp := []quad.Quad{in[0], in[1], in[2], in[3]}
+	exp[1].Object = quad.TypedString{Value: "false", Type: boolType}
+	// the lexical form is preserved, native booleans are not affected
+	strs := []string{
+		`"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`,
+		`"false"^^<http://www.w3.org/2001/XMLSchema#boolean>`,
+		`"1"^^<http://www.w3.org/2001/XMLSchema#boolean>`,
+		quad.StringOf(quad.Bool(true)),
+	}
+
+	ctx := context.Background()
+	var sizes [2]int
+	for i, compact := range []bool{false, true} {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, &pquads.Options{CompactBooleans: compact})
+		if _, err := w.WriteQuads(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		sizes[i] = buf.Len()
+		quads, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := in
+		if compact {
+			want = exp
+		}
+		if !reflect.DeepEqual(want, quads) {
+			t.Fatalf("corrupted quads:\n%#v\n%#v", want, quads)
+		}
+		if compact {
+			for i, q := range quads {
+				if s := quad.StringOf(q.Object); s != strs[i] {
+					t.Fatalf("unexpected object %d: %s, expected %s", i, s, strs[i])
+				}
+			}
+		}
+	}
+	if sizes[1] >= sizes[0] {
+		t.Fatalf("expected compact booleans to reduce size: %d vs %d", sizes[1], sizes[0])
+	}
+}
+
+func makeQuads(n int) []quad.Quad {
+	quads := make([]quad.Quad, 0, n)
+	for i := 0; i < n; i++ {
+		quads = append(quads, quad.Quad{
+			Subject:   quad.IRI(fmt.Sprintf("s%d", i/10)),
+			Predicate: quad.IRI(fmt.Sprintf("p%d", i%3)),
+			Object:    quad.String(fmt.Sprintf("object %d", i)),
+		})
+	}
+	return quads
+}
+
+func writeQuads(t testing.TB, opts *pquads.Options, quads []quad.Quad) []byte {
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, opts)
+	if _, err := w.WriteQuads(context.Background(), quads); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReaderProgress(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(1000)
+	data := writeQuads(t, nil, in)
+
+	zbuf := bytes.NewBuffer(nil)
+	zw := gzip.NewWriter(zbuf)
+	zw.Write(data)
+	zw.Close()
+
+	for _, c := range []struct {
+		name string
+		data []byte
+	}{
+		{"plain", data},
+		{"gzip", zbuf.Bytes()},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := pquads.NewReader(bytes.NewReader(c.data), 0)
+			defer r.Close()
+			if _, err := r.ReadQuad(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if _, n := r.Progress(); n != 1 {
+				t.Fatalf("unexpected quads count: %d", n)
+			}
+			out, err := quad.ReadAll(ctx, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(in[1:], out) {
+				t.Fatal("corrupted quads")
+			}
+			read, n := r.Progress()
+			if read != int64(len(c.data)) || n != len(in) {
+				t.Fatalf("unexpected progress: %d/%d bytes, %d/%d quads", read, len(c.data), n, len(in))
+			}
+			if _, err := r.ReadQuad(ctx); err != io.EOF {
+				t.Fatalf("expected EOF, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestConcat(t *testing.T) {
+	ctx := context.Background()
+	all := makeQuads(30)
+	opts := &pquads.Options{NoHeader: true}
+	var bodies []io.Reader
+	for i := 0; i < len(all); i += 10 {
+		bodies = append(bodies, bytes.NewReader(writeQuads(t, opts, all[i:i+10])))
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := pquads.Concat(buf, nil, 0, bodies...); err != nil {
+		t.Fatal(err)
+	}
+	out, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(all, out) {
+		t.Fatalf("corrupted quads:\n%v\n%v", all, out)
+	}
+
+	strict := writeQuads(t, &pquads.Options{NoHeader: true, Strict: true}, all[:10])
+	err = pquads.Concat(io.Discard, nil, 0, bytes.NewReader(strict))
+	if err == nil {
+		t.Fatal("expected options mismatch error")
+	}
+	for _, h := range []*pquads.Header{{NotStrict: true, Ids: true}, {NotStrict: true, SharedDictionary: true}} {
+		body := bytes.NewBuffer(nil)
+		if _, err = pio.NewWriter(body).WriteMsg(h); err != nil {
+			t.Fatal(err)
+		}
+		if err = pquads.Concat(io.Discard, nil, 0, body); err == nil {
+			t.Fatalf("expected an error for %+v", h)
+		}
+	}
+
+	// records larger than the default limit
+	big := []quad.Quad{quad.MakeIRI("s", "p", strings.Repeat("o", pquads.DefaultMaxSize), "")}
+	body := writeQuads(t, opts, big)
+	if err = pquads.Concat(io.Discard, nil, 0, bytes.NewReader(body)); err == nil {
+		t.Fatal("expected an error for a record over the limit")
+	}
+	buf.Reset()
+	if err = pquads.Concat(buf, nil, 2*pquads.DefaultMaxSize, bytes.NewReader(body)); err != nil {
+		t.Fatal(err)
+	}
+	out, err = quad.ReadAll(ctx, pquads.NewReader(buf, 2*pquads.DefaultMaxSize))
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(big, out) {
+		t.Fatal("corrupted quads")
+	}
+}
+
+func TestSectionReader(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(20)
+	data := writeQuads(t, nil, in)
+	prefix, suffix := []byte("container header"), writeQuads(t, nil, makeQuads(5))
+	file := append(append(append([]byte{}, prefix...), data...), suffix...)
+
+	r, err := pquads.NewSectionReader(bytes.NewReader(file), int64(len(prefix)), int64(len(data)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatal("corrupted quads")
+	}
+	if _, err = pquads.NewSectionReader(bytes.NewReader(file), 0, int64(len(data)), 0); err == nil {
+		t.Fatal("expected an error for invalid section")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(10)
+	for _, opts := range []pquads.Options{
+		{Full: false, Strict: false},
+		{Full: false, Strict: true},
+		{Full: true, Strict: false},
+		{Full: true, Strict: true},
+	} {
+		for _, skip := range []bool{false, true} {
+			buf := bytes.NewBuffer(nil)
+			w := pquads.NewWriter(buf, &opts)
+			if _, err := w.WriteQuads(ctx, in[:5]); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Checkpoint(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.WriteQuads(ctx, in[5:]); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Checkpoint(); err != nil {
+				t.Fatal(err)
+			}
+			r := pquads.NewReader(buf, 0)
+			for i := 0; i < len(in); i++ {
+				if i == 5 {
+					if _, ok := r.LastCheckpoint(); ok {
+						t.Fatal("unexpected checkpoint")
+					}
+				}
+				if skip {
+					if err := r.SkipQuad(ctx); err != nil {
+						t.Fatal(err)
+					}
+				} else if q, err := r.ReadQuad(ctx); err != nil {
+					t.Fatal(err)
+				} else if q != in[i] {
+					t.Fatalf("%+v: unexpected quad %d: %v", opts, i, q)
+				}
+				if i == 5 {
+					if cp, ok := r.LastCheckpoint(); !ok || cp != 5 {
+						t.Fatalf("unexpected checkpoint: %d, %v", cp, ok)
+					}
+				}
+			}
+			if _, err := r.ReadQuad(ctx); err != io.EOF {
+				t.Fatalf("expected EOF, got: %v", err)
+			}
+			if cp, _ := r.LastCheckpoint(); cp != len(in) {
+				t.Fatalf("unexpected checkpoint: %d", cp)
+			}
+		}
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	ctx := context.Background()
+	long := func(s string) string { return "http://example.org/a/very/long/iri/to/deduplicate/" + s }
+	var in []quad.Quad
+	for i := 0; i < 20; i++ {
+		// the same IRI is used in all directions
+		in = append(in,
+			quad.MakeIRI(long(fmt.Sprint(i)), long("p"), long("o"), long("g")),
+			quad.MakeIRI(long("o"), long("g"), long(fmt.Sprint(i)), long("p")),
+			quad.MakeIRI(long("g"), long("p"), long("o"), long("o")),
+		)
+	}
+	for _, opts := range []pquads.Options{
+		{Full: false, Strict: false},
+		{Full: false, Strict: true},
+		{Full: true, Strict: false},
+		{Full: true, Strict: true},
+	} {
+		plain := writeQuads(t, &opts, in)
+		opts.Dictionary = true
+		for _, cp := range []bool{false, true} {
+			buf := bytes.NewBuffer(nil)
+			w := pquads.NewWriter(buf, &opts)
+			for i, q := range in {
+				if cp && i%7 == 0 {
+					if err := w.Checkpoint(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := w.WriteQuad(ctx, q); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(plain) <= buf.Len() {
+				t.Fatalf("%+v: expected dictionary to reduce size: %d vs %d", opts, buf.Len(), len(plain))
+			}
+			for _, skip := range []bool{false, true} {
+				r := pquads.NewReader(bytes.NewReader(buf.Bytes()), 0)
+				if skip {
+					if err := r.SkipQuad(ctx); err != nil {
+						t.Fatal(err)
+					}
+				}
+				out, err := quad.ReadAll(ctx, r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				exp := in
+				if skip {
+					exp = in[1:]
+				}
+				if !reflect.DeepEqual(exp, out) {
+					t.Fatalf("%+v: corrupted quads", opts)
+				}
+			}
+		}
+	}
+}
+
+func TestConcatDictionary(t *testing.T) {
+	ctx := context.Background()
+	all := makeQuads(30)
+	opts := &pquads.Options{NoHeader: true, Dictionary: true}
+	var bodies []io.Reader
+	for i := 0; i < len(all); i += 10 {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		w.WriteQuads(ctx, all[i:i+5])
+		w.Checkpoint()
+		w.WriteQuads(ctx, all[i+5:i+10])
+		bodies = append(bodies, buf)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := pquads.Concat(buf, &pquads.Options{Dictionary: true}, 0, bodies...); err != nil {
+		t.Fatal(err)
+	}
+	r := pquads.NewReader(buf, 0)
+	for i := range all {
+		q, err := r.ReadQuad(ctx)
+		if err != nil {
+			t.Fatal(err)
+		} else if q != all[i] {
+			t.Fatalf("unexpected quad %d: %v", i, q)
+		}
+		if cp, _ := r.LastCheckpoint(); cp != i-i%5 {
+			t.Fatalf("unexpected checkpoint before quad %d: %d", i, cp)
+		}
+	}
+}
+
+func TestReaderPull(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(10)
+	var src pquads.Source = pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0)
+	var out []quad.Quad
+	for {
+		q, more, err := src.Pull(ctx)
+		if err != nil {
+			t.Fatal(err)
+		} else if !more {
+			break
+		}
+		out = append(out, q)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatal("corrupted quads")
+	}
+	if _, more, err := src.Pull(ctx); more || err != nil {
+		t.Fatalf("expected end of stream, got: %v, %v", more, err)
+	}
+
+	src = pquads.NewReader(bytes.NewReader([]byte("not a pquads file")), 0)
+	if _, more, err := src.Pull(ctx); more || err == nil {
+		t.Fatalf("expected an error, got: %v, %v", more, err)
+	}
+}
+
+func TestWriterMarshalError(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(10)
+	for _, opts := range []*pquads.Options{nil, {Full: true}} {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		if _, err := w.WriteQuads(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+		// invalid UTF-8 is rejected by default
+		bad := quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String("\xff")}
+		if err := w.WriteQuad(ctx, bad); err == nil {
+			t.Fatal("expected marshal error")
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatal("corrupted quads")
+		}
+	}
+}
+
+func TestWriterVersion(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(3)
+	for _, rec := range []bool{false, true} {
+		data := writeQuads(t, &pquads.Options{WriterVersion: rec}, in)
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		out, err := quad.ReadAll(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatal("corrupted quads")
+		}
+		if vers := r.WriterVersion(); rec != (vers != "") {
+			t.Fatalf("unexpected version (recorded=%v): %q", rec, vers)
+		}
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(50)
+	src := writeQuads(t, &pquads.Options{Full: true}, in)
+	for _, opts := range []*pquads.Options{
+		nil,
+		{Strict: true, Compress: true},
+		{Dictionary: true, BlockQuads: 7},
+	} {
+		buf := bytes.NewBuffer(nil)
+		n, err := pquads.Rewrite(buf, bytes.NewReader(src), 0, opts)
+		if err != nil {
+			t.Fatal(err)
+		} else if n != len(in) {
+			t.Fatalf("unexpected number of quads: %d", n)
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads", opts)
+		}
+	}
+
+	// deletes cannot be written without a changelog
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{Changelog: true})
+	w.WriteChange(ctx, in[0], pquads.OpAdd)
+	w.WriteChange(ctx, in[1], pquads.OpDelete)
+	w.Close()
+	if n, err := pquads.Rewrite(io.Discard, buf, 0, nil); err == nil || n != 1 {
+		t.Fatalf("expected an error after 1 quad, got: %d, %v", n, err)
+	}
+}
+
+func TestVerifyEnd(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(50)
+	junk := []byte("trailing junk")
+	readAll := func(data []byte) (*pquads.Reader, error) {
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		for {
+			if _, err := r.ReadQuad(ctx); err == io.EOF {
+				return r, nil
+			} else if err != nil {
+				return r, err
+			}
+		}
+	}
+	for _, c := range []struct {
+		name     string
+		opts     *pquads.Options
+		trailing bool // trailing data is reported only by VerifyEnd
+	}{
+		{name: "plain", opts: nil},
+		{name: "index", opts: &pquads.Options{BlockQuads: 20}, trailing: true},
+		{name: "gzip", opts: &pquads.Options{Compress: true}, trailing: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			data := writeQuads(t, c.opts, quads)
+			r, err := readAll(data)
+			if err != nil {
+				t.Fatal(err)
+			} else if err = r.VerifyEnd(); err != nil {
+				t.Fatal(err)
+			}
+
+			data = append(data[:len(data):len(data)], junk...)
+			r, err = readAll(data)
+			if !c.trailing {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			} else if c.opts.BlockQuads != 0 && err != nil {
+				t.Fatal(err)
+			}
+			if err = r.VerifyEnd(); !errors.Is(err, pquads.ErrTrailingData) {
+				t.Fatalf("expected trailing data error, got: %v", err)
+			}
+		})
+	}
+
+	r := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, quads)), 0)
+	if _, err := r.ReadQuad(ctx); err != nil {
+		t.Fatal(err)
+	} else if err = r.VerifyEnd(); err == nil {
+		t.Fatal("expected an error for a partially read stream")
+	}
+}
+
+func TestEmptyFile(t *testing.T) {
+	ctx := context.Background()
+	for _, opts := range []*pquads.Options{
+		nil,
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{BlockQuads: 10},
+		{Compress: true},
+		{Compress: true, CompressResetQuads: 10},
+		{RollingCRC: true},
+		{RollingCRC: true, BlockQuads: 10},
+		{QuadHashes: true, Changelog: true, SubjectGroups: true, Sources: true},
+	} {
+		data := writeQuads(t, opts, nil)
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		if _, err := r.ReadQuad(ctx); err != io.EOF {
+			t.Fatalf("%+v: expected EOF, got: %v", opts, err)
+		} else if err = r.VerifyEnd(); err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		got, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		} else if len(got) != 0 {
+			t.Fatalf("%+v: unexpected quads: %v", opts, got)
+		}
+		if opts != nil && opts.BlockQuads != 0 {
+			ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+			if err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			} else if ir.NumQuads() != 0 || ir.NumBlocks() != 1 {
+				t.Fatalf("%+v: unexpected index: %d quads, %d blocks", opts, ir.NumQuads(), ir.NumBlocks())
+			}
+		}
+		if opts != nil && opts.RollingCRC {
+			if _, n, err := pquads.VerifyRollingSum(bytes.NewReader(data), 0); err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			} else if n != 0 {
+				t.Fatalf("%+v: unexpected number of quads: %d", opts, n)
+			}
+		}
+	}
+}
+
+func TestFormatVersion(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(10)
+	data := writeQuads(t, nil, in)
+	if data[4] != 2 {
+		t.Fatalf("unexpected format version: %d", data[4])
+	}
+	// files of the previous version are still read, and newer versions are rejected
+	for _, vers := range []byte{1, 3} {
+		data := append([]byte{}, data...)
+		data[4] = vers
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+		if vers == 1 && (err != nil || !reflect.DeepEqual(out, in)) {
+			t.Fatalf("cannot read version 1: %v", err)
+		} else if vers == 3 && err == nil {
+			t.Fatal("expected an error for an unsupported version")
+		}
+	}
+}
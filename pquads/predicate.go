@@ -0,0 +1,120 @@
+package pquads
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cayleygraph/quad"
+)
+
+// scanPredicates calls fn for the predicate of each quad in the file, with quads in subject groups expanded.
+//
+// Only the predicate field of each record is read, and fn receives the encoded predicate as a key and a nil value,
+// except for files that are decoded completely (see scanDecodes). In this case the key is the N-Quads form
+// of the predicate (see quad.StringOf) and the value is set.
+func scanPredicates(qr *Reader, fn func(key string, val quad.Value)) error {
+	if qr.err != nil {
+		return qr.err
+	}
+	if scanDecodes(&qr.opts) {
+		ctx := context.Background()
+		for {
+			q, _, err := qr.ReadChange(ctx)
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			fn(quad.StringOf(q.Predicate), q.Predicate)
+		}
+	}
+	var prev string
+	for i := 0; ; i++ {
+		data, err := qr.pr.ReadRaw()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("quad %d: %w", i, err)
+		}
+		if hasField(data, checkpointField) || hasField(data, indexField) || hasField(data, footerField) {
+			prev = ""
+			continue
+		} else if hasField(data, sourceField) {
+			continue
+		}
+		if p, ok := fieldBytes(data, 2); ok {
+			if string(p) != prev {
+				prev = string(p)
+			}
+		} else if prev == "" {
+			return fmt.Errorf("quad %d: no predicate", i)
+		}
+		fn(prev, nil)
+	}
+}
+
+// scanDecodes reports if files with given options are decoded completely when scanning predicates or objects:
+// values of files with a dictionary, language tag dictionary or time deltas depend on the preceding records,
+// and subject groups contain several quads.
+func scanDecodes(opts *Options) bool {
+	return opts.valueState() || opts.SubjectGroups
+}
+
+// decodePredicate decodes a predicate key returned by scanPredicates for a file without a dictionary.
+func decodePredicate(strict bool, key string) (quad.Value, error) {
+	if strict {
+		var ref StrictQuad_Ref
+		if err := ref.UnmarshalVT([]byte(key)); err != nil {
+			return nil, err
+		}
+		return ref.ToNative(), nil
+	}
+	var v Value
+	if err := v.UnmarshalVT([]byte(key)); err != nil {
+		return nil, err
+	}
+	return nativeValue(&v)
+}
+
+// CountByPredicate counts quads with a given predicate in a pquads file. Predicates are compared by their
+// N-Quads form (see quad.StringOf), thus quad.IRI values must be in the same (full or short) form as in the file.
+//
+// Like TopPredicates, it only reads the predicate field of each quad, and decodes it only when it differs
+// from the predicate of the previous quad. Files with a dictionary, language tag dictionary, time deltas
+// or subject groups are decoded completely.
+// Quads with changelog operations are counted regardless of the operation.
+func CountByPredicate(r io.Reader, maxSize int, p quad.Value) (int, error) {
+	qr := NewReader(r, maxSize)
+	defer qr.Close()
+	want := quad.StringOf(p)
+	var (
+		n       int
+		last    string
+		decoded bool // last predicate was decoded
+		match   bool
+		err     error
+	)
+	full := scanDecodes(&qr.opts)
+	serr := scanPredicates(qr, func(key string, v quad.Value) {
+		if err != nil {
+			return
+		}
+		if full {
+			match = key == want
+		} else if !decoded || key != last {
+			last, decoded = key, true
+			if v, err = decodePredicate(qr.opts.Strict, key); err != nil {
+				return
+			}
+			match = quad.StringOf(v) == want
+		}
+		if match {
+			n++
+		}
+	})
+	if serr != nil {
+		return n, serr
+	}
+	return n, err
+}
@@ -0,0 +1,100 @@
+package pquads
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// srcState tracks sources written by the Writer.
+type srcState struct {
+	name  string            // current source
+	ids   map[string]uint64 // ids of sources written since the last checkpoint
+	dirty bool              // the current source is not written yet
+}
+
+// reset forgets all source ids, as done by the checkpoint.
+func (s *srcState) reset() {
+	s.ids = nil
+	s.dirty = s.name != ""
+}
+
+// SetSource sets the name of the source for all subsequent quads, for example the name of the file they were
+// read from. An empty name means that quads have no source. It requires Options.Sources. See Reader.SourceName.
+//
+// The source is written as a separate record before the next quad, thus quads themselves have no overhead.
+// Each switch of the source costs a few bytes, and the name is written only when it's used for the first
+// time (after the start of the stream or a checkpoint); switching back to it later only writes its id.
+func (w *Writer) SetSource(name string) error {
+	if w.err != nil {
+		return w.err
+	} else if !w.opts.Sources {
+		return errors.New("sources require Options.Sources")
+	} else if !utf8.ValidString(name) {
+		return fmt.Errorf("%w in source name: %q", ErrInvalidUTF8, name)
+	}
+	if name != w.src.name {
+		w.src.name, w.src.dirty = name, true
+	}
+	return nil
+}
+
+// writeSource writes the source record if the source was changed since the last quad.
+func (w *Writer) writeSource() error {
+	if !w.src.dirty {
+		return nil
+	}
+	rec := &SourceRecord{}
+	if name := w.src.name; name != "" {
+		id, ok := w.src.ids[name]
+		if !ok {
+			if w.src.ids == nil {
+				w.src.ids = make(map[string]uint64)
+			}
+			id = uint64(len(w.src.ids)) + 1
+			w.src.ids[name] = id
+			rec.Name = name
+		}
+		rec.Id = id
+	}
+	var m proto.Message
+	if w.opts.Strict {
+		m = &StrictQuad{Source: rec}
+	} else {
+		m = &WireQuad{Source: rec}
+	}
+	var n int
+	n, w.err = w.pw.WriteMsg(m)
+	w.off += int64(n)
+	w.src.dirty = false
+	return w.err
+}
+
+// SourceName returns the name of the source of the last quad read, as set by Writer.SetSource.
+// It returns an empty string if the quad has no source.
+func (r *Reader) SourceName() string {
+	return r.srcName
+}
+
+func (r *Reader) setSource(rec *SourceRecord) error {
+	if !r.opts.Sources {
+		return fmt.Errorf("unexpected source record at quad %d", r.n)
+	}
+	switch {
+	case rec.Id == 0:
+		r.srcName = ""
+	case rec.Name != "":
+		if rec.Id != uint64(len(r.srcNames))+1 {
+			return fmt.Errorf("invalid id for source %q at quad %d: %d", rec.Name, r.n, rec.Id)
+		}
+		r.srcNames = append(r.srcNames, rec.Name)
+		r.srcName = rec.Name
+	case rec.Id > uint64(len(r.srcNames)):
+		return fmt.Errorf("unknown source id at quad %d: %d", r.n, rec.Id)
+	default:
+		r.srcName = r.srcNames[rec.Id-1]
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestSources(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(40)
+	names := []string{"a.nq", "b.nq", "a.nq", ""}
+	source := func(i int) string { return names[i/10] }
+	for _, opts := range []*pquads.Options{
+		{},
+		{Full: true},
+		{Strict: true, Dictionary: true},
+		{BlockQuads: 7},
+	} {
+		opts.Sources = true
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		for i, q := range quads {
+			if err := w.SetSource(source(i)); err != nil {
+				t.Fatal(err)
+			} else if err = w.WriteQuad(ctx, q); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+
+		check := func(name string, data []byte, skip int) {
+			r := pquads.NewReader(bytes.NewReader(data), 0)
+			for i := 0; i < skip; i++ {
+				if err := r.SkipQuad(ctx); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := skip; ; i++ {
+				q, err := r.ReadQuad(ctx)
+				if err == io.EOF {
+					if i != len(quads) {
+						t.Fatalf("%s %+v: expected %d quads, got %d", name, opts, len(quads), i)
+					}
+					return
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				if q != quads[i] {
+					t.Fatalf("%s %+v: quad %d: %v vs %v", name, opts, i, q, quads[i])
+				} else if s := r.SourceName(); s != source(i) {
+					t.Fatalf("%s %+v: source of quad %d: %q vs %q", name, opts, i, s, source(i))
+				}
+			}
+		}
+		check("read", data, 0)
+		check("skip", data, 15)
+
+		var out bytes.Buffer
+		if _, err := pquads.Rewrite(&out, bytes.NewReader(data), 0, &pquads.Options{Sources: true}); err != nil {
+			t.Fatal(err)
+		}
+		check("rewrite", out.Bytes(), 0)
+	}
+
+	w := pquads.NewWriter(io.Discard, nil)
+	if err := w.SetSource("a.nq"); err == nil {
+		t.Fatal("expected an error without the option")
+	}
+}
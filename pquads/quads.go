@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/voc/xsd"
+	"google.golang.org/protobuf/proto"
 )
 
 // MakeValue converts quad.Value to its protobuf representation.
@@ -39,13 +41,7 @@ func MakeValue(qv quad.Value) *Value {
 	case quad.Bool:
 		return &Value{Value: &Value_Boolean{bool(v)}}
 	case quad.Time:
-		t := time.Time(v)
-		seconds := t.Unix()
-		nanos := int32(t.Sub(time.Unix(seconds, 0)))
-		return &Value{Value: &Value_Time{&Value_Timestamp{
-			Seconds: seconds,
-			Nanos:   nanos,
-		}}}
+		return &Value{Value: &Value_Time{makeTimestamp(time.Time(v))}}
 	default:
 		panic(fmt.Errorf("unsupported type: %T", qv))
 	}
@@ -67,11 +63,16 @@ func UnmarshalValue(ctx context.Context, data []byte) (quad.Value, error) {
 	var v Value
 	if err := v.UnmarshalVT(data); err != nil {
 		return nil, err
+	} else if isTimeDelta(&v) {
+		return nil, errTimeDelta
 	}
-	return v.ToNative(), nil
+	return nativeValue(&v)
 }
 
 // ToNative converts protobuf Value to quad.Value.
+//
+// Time deltas cannot be converted without the previous timestamp of the stream, which is known only to the Reader,
+// thus they are returned as nil.
 func (m *Value) ToNative() (qv quad.Value) {
 	if m == nil {
 		return nil
@@ -102,13 +103,15 @@ func (m *Value) ToNative() (qv quad.Value) {
 	case *Value_Boolean:
 		return quad.Bool(v.Boolean)
 	case *Value_Time:
-		var t time.Time
-		if v.Time == nil {
-			t = time.Unix(0, 0).UTC()
-		} else {
-			t = time.Unix(v.Time.Seconds, int64(v.Time.Nanos)).UTC()
-		}
-		return quad.Time(t)
+		return quad.Time(timestampTime(v.Time))
+	case *Value_BinStr:
+		return v.BinStr.ToNative()
+	case *Value_Codec:
+		return v.Codec.ToNative()
+	case *Value_TimeDelta:
+		return nil
+	case *Value_XsdBoolean:
+		return xsdBool(v.XsdBoolean)
 	default:
 		panic(fmt.Errorf("unsupported type: %T", m.Value))
 	}
@@ -139,6 +142,49 @@ func MakeQuad(q quad.Quad) *Quad {
 	}
 }
 
+// xsdBooleanIRI is the full datatype IRI of compact booleans.
+var xsdBooleanIRI = quad.IRI(xsd.Boolean).Full()
+
+// xsdBool returns an xsd:boolean typed string in canonical lexical form.
+func xsdBool(b bool) quad.TypedString {
+	s := "false"
+	if b {
+		s = "true"
+	}
+	return quad.TypedString{Value: quad.String(s), Type: xsdBooleanIRI}
+}
+
+// compactBool normalizes xsd:boolean typed strings in canonical lexical form to the full datatype IRI,
+// which is how they are read back once stored compactly (see compactBools). Any other value is returned unchanged.
+func compactBool(v quad.Value) quad.Value {
+	ts, ok := v.(quad.TypedString)
+	if !ok || ts.Type.Short() != xsd.Boolean {
+		return v
+	}
+	switch ts.Value {
+	case "true", "false":
+		return quad.TypedString{Value: ts.Value, Type: xsdBooleanIRI}
+	}
+	return v
+}
+
+// compactBools replaces the object of a record normalized by compactBool with a compact boolean.
+func compactBools(m proto.Message) {
+	var v *Value
+	switch m := m.(type) {
+	case *WireQuad:
+		v = m.Object
+	case *StrictQuad:
+		v = m.Object
+	}
+	if ts := v.GetTypedStr(); ts != nil && quad.IRI(ts.Type) == xsdBooleanIRI {
+		switch ts.Value {
+		case "true", "false":
+			v.Value = &Value_XsdBoolean{XsdBoolean: ts.Value == "true"}
+		}
+	}
+}
+
 func makeRef(v quad.Value) (*StrictQuad_Ref, error) {
 	if v == nil {
 		return nil, nil
@@ -149,6 +149,24 @@ type WireQuad struct {
 	Predicate *Value `protobuf:"bytes,2,opt,name=predicate,proto3" json:"predicate,omitempty"`
 	Object    *Value `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
 	Label     *Value `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	// Checkpoint is set if the message is a checkpoint record instead of a quad.
+	Checkpoint *Checkpoint `protobuf:"bytes,5,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	// Op is a changelog operation for the quad: 0 - add, 1 - delete, 2 - upsert. See Header.changelog.
+	Op uint32 `protobuf:"varint,6,opt,name=op,proto3" json:"op,omitempty"`
+	// Index is set if the message is a block index record instead of a quad.
+	Index *BlockIndex `protobuf:"bytes,7,opt,name=index,proto3" json:"index,omitempty"`
+	// Footer is set if the message is a footer record instead of a quad.
+	Footer *Footer `protobuf:"bytes,8,opt,name=footer,proto3" json:"footer,omitempty"`
+	// Hash of the quad, as calculated by quad.HashQuad. See Header.hashes.
+	Hash []byte `protobuf:"bytes,9,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Version of the quad, see Writer.WriteQuadVersioned.
+	Version *Value `protobuf:"bytes,10,opt,name=version,proto3" json:"version,omitempty"`
+	// Group is set if the message is a subject group record instead of a quad. See Header.subject_groups.
+	Group []*WireQuad `protobuf:"bytes,11,rep,name=group,proto3" json:"group,omitempty"`
+	// Source is set if the message is a source record instead of a quad. See Header.sources.
+	Source *SourceRecord `protobuf:"bytes,12,opt,name=source,proto3" json:"source,omitempty"`
+	// Flags of the quad, see Writer.WriteQuadFlags. The meaning of bits is defined by the application.
+	Flags uint32 `protobuf:"varint,13,opt,name=flags,proto3" json:"flags,omitempty"`
 }
 
 func (x *WireQuad) Reset() {
@@ -211,6 +229,69 @@ func (x *WireQuad) GetLabel() *Value {
 	return nil
 }
 
+func (x *WireQuad) GetCheckpoint() *Checkpoint {
+	if x != nil {
+		return x.Checkpoint
+	}
+	return nil
+}
+
+func (x *WireQuad) GetOp() uint32 {
+	if x != nil {
+		return x.Op
+	}
+	return 0
+}
+
+func (x *WireQuad) GetIndex() *BlockIndex {
+	if x != nil {
+		return x.Index
+	}
+	return nil
+}
+
+func (x *WireQuad) GetFooter() *Footer {
+	if x != nil {
+		return x.Footer
+	}
+	return nil
+}
+
+func (x *WireQuad) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *WireQuad) GetVersion() *Value {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *WireQuad) GetGroup() []*WireQuad {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *WireQuad) GetSource() *SourceRecord {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *WireQuad) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
 // WireQuadRaw is the same as WireQuad, but doesn't decode underlying values.
 type WireQuadRaw struct {
 	state         protoimpl.MessageState
@@ -293,6 +374,24 @@ type StrictQuad struct {
 	Predicate *StrictQuad_Ref `protobuf:"bytes,2,opt,name=predicate,proto3" json:"predicate,omitempty"`
 	Object    *Value          `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
 	Label     *StrictQuad_Ref `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	// Checkpoint is set if the message is a checkpoint record instead of a quad.
+	Checkpoint *Checkpoint `protobuf:"bytes,5,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	// Op is a changelog operation for the quad: 0 - add, 1 - delete, 2 - upsert. See Header.changelog.
+	Op uint32 `protobuf:"varint,6,opt,name=op,proto3" json:"op,omitempty"`
+	// Index is set if the message is a block index record instead of a quad.
+	Index *BlockIndex `protobuf:"bytes,7,opt,name=index,proto3" json:"index,omitempty"`
+	// Footer is set if the message is a footer record instead of a quad.
+	Footer *Footer `protobuf:"bytes,8,opt,name=footer,proto3" json:"footer,omitempty"`
+	// Hash of the quad, as calculated by quad.HashQuad. See Header.hashes.
+	Hash []byte `protobuf:"bytes,9,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Version of the quad, see Writer.WriteQuadVersioned.
+	Version *Value `protobuf:"bytes,10,opt,name=version,proto3" json:"version,omitempty"`
+	// Group is set if the message is a subject group record instead of a quad. See Header.subject_groups.
+	Group []*StrictQuad `protobuf:"bytes,11,rep,name=group,proto3" json:"group,omitempty"`
+	// Source is set if the message is a source record instead of a quad. See Header.sources.
+	Source *SourceRecord `protobuf:"bytes,12,opt,name=source,proto3" json:"source,omitempty"`
+	// Flags of the quad, see Writer.WriteQuadFlags. The meaning of bits is defined by the application.
+	Flags uint32 `protobuf:"varint,13,opt,name=flags,proto3" json:"flags,omitempty"`
 }
 
 func (x *StrictQuad) Reset() {
@@ -355,6 +454,69 @@ func (x *StrictQuad) GetLabel() *StrictQuad_Ref {
 	return nil
 }
 
+func (x *StrictQuad) GetCheckpoint() *Checkpoint {
+	if x != nil {
+		return x.Checkpoint
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetOp() uint32 {
+	if x != nil {
+		return x.Op
+	}
+	return 0
+}
+
+func (x *StrictQuad) GetIndex() *BlockIndex {
+	if x != nil {
+		return x.Index
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetFooter() *Footer {
+	if x != nil {
+		return x.Footer
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetVersion() *Value {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetGroup() []*StrictQuad {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetSource() *SourceRecord {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *StrictQuad) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
 // StrictQuadRaw is the same as StrictQuad, but doesn't decode underlying values.
 type StrictQuadRaw struct {
 	state         protoimpl.MessageState
@@ -444,6 +606,11 @@ type Value struct {
 	//	*Value_Float
 	//	*Value_Boolean
 	//	*Value_Time
+	//	*Value_Ref
+	//	*Value_BinStr
+	//	*Value_Codec
+	//	*Value_TimeDelta
+	//	*Value_XsdBoolean
 	Value isValue_Value `protobuf_oneof:"value"`
 }
 
@@ -556,6 +723,41 @@ func (x *Value) GetTime() *Value_Timestamp {
 	return nil
 }
 
+func (x *Value) GetRef() uint64 {
+	if x, ok := x.GetValue().(*Value_Ref); ok {
+		return x.Ref
+	}
+	return 0
+}
+
+func (x *Value) GetBinStr() *Value_BinaryString {
+	if x, ok := x.GetValue().(*Value_BinStr); ok {
+		return x.BinStr
+	}
+	return nil
+}
+
+func (x *Value) GetCodec() *Value_Encoded {
+	if x, ok := x.GetValue().(*Value_Codec); ok {
+		return x.Codec
+	}
+	return nil
+}
+
+func (x *Value) GetTimeDelta() int64 {
+	if x, ok := x.GetValue().(*Value_TimeDelta); ok {
+		return x.TimeDelta
+	}
+	return 0
+}
+
+func (x *Value) GetXsdBoolean() bool {
+	if x, ok := x.GetValue().(*Value_XsdBoolean); ok {
+		return x.XsdBoolean
+	}
+	return false
+}
+
 type isValue_Value interface {
 	isValue_Value()
 }
@@ -600,6 +802,30 @@ type Value_Time struct {
 	Time *Value_Timestamp `protobuf:"bytes,10,opt,name=time,proto3,oneof"`
 }
 
+type Value_Ref struct {
+	// Ref is an index of a value in the dictionary, see Header.dictionary.
+	Ref uint64 `protobuf:"varint,11,opt,name=ref,proto3,oneof"`
+}
+
+type Value_BinStr struct {
+	BinStr *Value_BinaryString `protobuf:"bytes,12,opt,name=bin_str,json=binStr,proto3,oneof"`
+}
+
+type Value_Codec struct {
+	Codec *Value_Encoded `protobuf:"bytes,13,opt,name=codec,proto3,oneof"`
+}
+
+type Value_TimeDelta struct {
+	// TimeDelta is a timestamp encoded as a difference in nanoseconds from the previous one, see Header.time_deltas.
+	TimeDelta int64 `protobuf:"zigzag64,14,opt,name=time_delta,json=timeDelta,proto3,oneof"`
+}
+
+type Value_XsdBoolean struct {
+	// XsdBoolean is an xsd:boolean typed string in canonical lexical form ("true" or "false"),
+	// see Options.CompactBooleans.
+	XsdBoolean bool `protobuf:"varint,15,opt,name=xsd_boolean,json=xsdBoolean,proto3,oneof"`
+}
+
 func (*Value_Raw) isValue_Value() {}
 
 func (*Value_Str) isValue_Value() {}
@@ -620,20 +846,34 @@ func (*Value_Boolean) isValue_Value() {}
 
 func (*Value_Time) isValue_Value() {}
 
-type Header struct {
+func (*Value_Ref) isValue_Value() {}
+
+func (*Value_BinStr) isValue_Value() {}
+
+func (*Value_Codec) isValue_Value() {}
+
+func (*Value_TimeDelta) isValue_Value() {}
+
+func (*Value_XsdBoolean) isValue_Value() {}
+
+// Checkpoint marks a position in the stream where decoding can be resumed.
+//
+// It is written as WireQuad or StrictQuad message (depending on the header) with only the checkpoint field set.
+// Values are never carried over across a checkpoint: the first quad after it has all directions set,
+// and the value dictionary (if enabled) is reset.
+type Checkpoint struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Full is set if encoder always writes every quad directions instead of
-	// skipping duplicated values on each direction (except label) for subsequent quads.
-	Full bool `protobuf:"varint,1,opt,name=full,proto3" json:"full,omitempty"`
-	// NotStrict is set if encoder emits WireQuad instead of StrictQuad messages.
-	NotStrict bool `protobuf:"varint,2,opt,name=not_strict,json=notStrict,proto3" json:"not_strict,omitempty"`
+	// Ordinal is the number of quads written before the checkpoint.
+	Ordinal uint64 `protobuf:"varint,1,opt,name=ordinal,proto3" json:"ordinal,omitempty"`
+	// Crc is the rolling CRC of the stream preceding this record. See Header.rolling_crc.
+	Crc uint32 `protobuf:"fixed32,2,opt,name=crc,proto3" json:"crc,omitempty"`
 }
 
-func (x *Header) Reset() {
-	*x = Header{}
+func (x *Checkpoint) Reset() {
+	*x = Checkpoint{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -641,13 +881,13 @@ func (x *Header) Reset() {
 	}
 }
 
-func (x *Header) String() string {
+func (x *Checkpoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Header) ProtoMessage() {}
+func (*Checkpoint) ProtoMessage() {}
 
-func (x *Header) ProtoReflect() protoreflect.Message {
+func (x *Checkpoint) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -659,39 +899,40 @@ func (x *Header) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Header.ProtoReflect.Descriptor instead.
-func (*Header) Descriptor() ([]byte, []int) {
+// Deprecated: Use Checkpoint.ProtoReflect.Descriptor instead.
+func (*Checkpoint) Descriptor() ([]byte, []int) {
 	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Header) GetFull() bool {
+func (x *Checkpoint) GetOrdinal() uint64 {
 	if x != nil {
-		return x.Full
+		return x.Ordinal
 	}
-	return false
+	return 0
 }
 
-func (x *Header) GetNotStrict() bool {
+func (x *Checkpoint) GetCrc() uint32 {
 	if x != nil {
-		return x.NotStrict
+		return x.Crc
 	}
-	return false
+	return 0
 }
 
-type StrictQuad_Ref struct {
+// SourceRecord sets the source of all subsequent quads, until the next source record or checkpoint.
+type SourceRecord struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Value:
-	//
-	//	*StrictQuad_Ref_BnodeLabel
-	//	*StrictQuad_Ref_Iri
-	Value isStrictQuad_Ref_Value `protobuf_oneof:"value"`
+	// Id of the source, starting from 1. Zero means that subsequent quads have no source.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name of the source. It's only set when the id is used for the first time after the start of the stream
+	// or the last checkpoint, and it's omitted when switching back to the source later.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *StrictQuad_Ref) Reset() {
-	*x = StrictQuad_Ref{}
+func (x *SourceRecord) Reset() {
+	*x = SourceRecord{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -699,13 +940,13 @@ func (x *StrictQuad_Ref) Reset() {
 	}
 }
 
-func (x *StrictQuad_Ref) String() string {
+func (x *SourceRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StrictQuad_Ref) ProtoMessage() {}
+func (*SourceRecord) ProtoMessage() {}
 
-func (x *StrictQuad_Ref) ProtoReflect() protoreflect.Message {
+func (x *SourceRecord) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -717,59 +958,45 @@ func (x *StrictQuad_Ref) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StrictQuad_Ref.ProtoReflect.Descriptor instead.
-func (*StrictQuad_Ref) Descriptor() ([]byte, []int) {
-	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{3, 0}
-}
-
-func (m *StrictQuad_Ref) GetValue() isStrictQuad_Ref_Value {
-	if m != nil {
-		return m.Value
-	}
-	return nil
+// Deprecated: Use SourceRecord.ProtoReflect.Descriptor instead.
+func (*SourceRecord) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *StrictQuad_Ref) GetBnodeLabel() string {
-	if x, ok := x.GetValue().(*StrictQuad_Ref_BnodeLabel); ok {
-		return x.BnodeLabel
+func (x *SourceRecord) GetId() uint64 {
+	if x != nil {
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *StrictQuad_Ref) GetIri() string {
-	if x, ok := x.GetValue().(*StrictQuad_Ref_Iri); ok {
-		return x.Iri
+func (x *SourceRecord) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-type isStrictQuad_Ref_Value interface {
-	isStrictQuad_Ref_Value()
-}
-
-type StrictQuad_Ref_BnodeLabel struct {
-	BnodeLabel string `protobuf:"bytes,2,opt,name=bnode_label,json=bnodeLabel,proto3,oneof"`
-}
-
-type StrictQuad_Ref_Iri struct {
-	Iri string `protobuf:"bytes,3,opt,name=iri,proto3,oneof"`
-}
-
-func (*StrictQuad_Ref_BnodeLabel) isStrictQuad_Ref_Value() {}
-
-func (*StrictQuad_Ref_Iri) isStrictQuad_Ref_Value() {}
-
-type Value_TypedString struct {
+// IDQuad is a quad written by IDWriter, with values replaced by ids from the dictionary.
+//
+// Dictionary entries are written as IDQuad messages with only the value field set. An entry always precedes
+// quads that refer to it. Quad values are never carried over from the previous quad.
+type IDQuad struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Type  string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// Ids of values for each direction. Zero means no value.
+	Subject   uint64 `protobuf:"varint,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Predicate uint64 `protobuf:"varint,2,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	Object    uint64 `protobuf:"varint,3,opt,name=object,proto3" json:"object,omitempty"`
+	Label     uint64 `protobuf:"varint,4,opt,name=label,proto3" json:"label,omitempty"`
+	// Value is set if the message is a dictionary entry instead of a quad.
+	Value *IDValue `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (x *Value_TypedString) Reset() {
-	*x = Value_TypedString{}
+func (x *IDQuad) Reset() {
+	*x = IDQuad{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -777,13 +1004,13 @@ func (x *Value_TypedString) Reset() {
 	}
 }
 
-func (x *Value_TypedString) String() string {
+func (x *IDQuad) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Value_TypedString) ProtoMessage() {}
+func (*IDQuad) ProtoMessage() {}
 
-func (x *Value_TypedString) ProtoReflect() protoreflect.Message {
+func (x *IDQuad) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -795,36 +1022,58 @@ func (x *Value_TypedString) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Value_TypedString.ProtoReflect.Descriptor instead.
-func (*Value_TypedString) Descriptor() ([]byte, []int) {
-	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 0}
+// Deprecated: Use IDQuad.ProtoReflect.Descriptor instead.
+func (*IDQuad) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Value_TypedString) GetValue() string {
+func (x *IDQuad) GetSubject() uint64 {
 	if x != nil {
-		return x.Value
+		return x.Subject
 	}
-	return ""
+	return 0
 }
 
-func (x *Value_TypedString) GetType() string {
+func (x *IDQuad) GetPredicate() uint64 {
 	if x != nil {
-		return x.Type
+		return x.Predicate
 	}
-	return ""
+	return 0
 }
 
-type Value_LangString struct {
+func (x *IDQuad) GetObject() uint64 {
+	if x != nil {
+		return x.Object
+	}
+	return 0
+}
+
+func (x *IDQuad) GetLabel() uint64 {
+	if x != nil {
+		return x.Label
+	}
+	return 0
+}
+
+func (x *IDQuad) GetValue() *IDValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// IDValue is a dictionary entry that assigns an id to a value.
+type IDValue struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Lang  string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	Id    uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Value *Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (x *Value_LangString) Reset() {
-	*x = Value_LangString{}
+func (x *IDValue) Reset() {
+	*x = IDValue{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -832,13 +1081,13 @@ func (x *Value_LangString) Reset() {
 	}
 }
 
-func (x *Value_LangString) String() string {
+func (x *IDValue) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Value_LangString) ProtoMessage() {}
+func (*IDValue) ProtoMessage() {}
 
-func (x *Value_LangString) ProtoReflect() protoreflect.Message {
+func (x *IDValue) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -850,27 +1099,946 @@ func (x *Value_LangString) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Value_LangString.ProtoReflect.Descriptor instead.
-func (*Value_LangString) Descriptor() ([]byte, []int) {
-	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 1}
+// Deprecated: Use IDValue.ProtoReflect.Descriptor instead.
+func (*IDValue) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *Value_LangString) GetValue() string {
+func (x *IDValue) GetId() uint64 {
 	if x != nil {
-		return x.Value
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *Value_LangString) GetLang() string {
+func (x *IDValue) GetValue() *Value {
 	if x != nil {
-		return x.Lang
+		return x.Value
 	}
-	return ""
+	return nil
 }
 
-// From https://github.com/golang/protobuf/blob/master/ptypes/timestamp/timestamp.proto
-type Value_Timestamp struct {
+// BlockIndex lists blocks of the file. Each block except the first starts with a checkpoint record.
+//
+// It is written as WireQuad or StrictQuad message (depending on the header) with only the index field set,
+// right before the footer record at the end of the file.
+type BlockIndex struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blocks []*BlockIndex_Block `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	// Quads is the total number of quads in the file.
+	Quads uint64 `protobuf:"varint,2,opt,name=quads,proto3" json:"quads,omitempty"`
+	// FirstSubject and LastSubject are the subjects of the first and the last quad in the file.
+	// They are only set for files with sorted quads, written by MergeIndexed.
+	FirstSubject *Value `protobuf:"bytes,3,opt,name=first_subject,json=firstSubject,proto3" json:"first_subject,omitempty"`
+	LastSubject  *Value `protobuf:"bytes,4,opt,name=last_subject,json=lastSubject,proto3" json:"last_subject,omitempty"`
+}
+
+func (x *BlockIndex) Reset() {
+	*x = BlockIndex{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockIndex) ProtoMessage() {}
+
+func (x *BlockIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockIndex.ProtoReflect.Descriptor instead.
+func (*BlockIndex) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BlockIndex) GetBlocks() []*BlockIndex_Block {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *BlockIndex) GetQuads() uint64 {
+	if x != nil {
+		return x.Quads
+	}
+	return 0
+}
+
+func (x *BlockIndex) GetFirstSubject() *Value {
+	if x != nil {
+		return x.FirstSubject
+	}
+	return nil
+}
+
+func (x *BlockIndex) GetLastSubject() *Value {
+	if x != nil {
+		return x.LastSubject
+	}
+	return nil
+}
+
+// Footer is the last record of a file with a block index.
+//
+// It is written as WireQuad or StrictQuad message with only the footer field set, thus the record has a fixed size
+// of 12 bytes (including the length prefix) and can be read from the end of the file.
+type Footer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IndexOffset is the offset of the block index record from the start of the file.
+	IndexOffset uint64 `protobuf:"fixed64,1,opt,name=index_offset,json=indexOffset,proto3" json:"index_offset,omitempty"`
+}
+
+func (x *Footer) Reset() {
+	*x = Footer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Footer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Footer) ProtoMessage() {}
+
+func (x *Footer) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Footer.ProtoReflect.Descriptor instead.
+func (*Footer) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Footer) GetIndexOffset() uint64 {
+	if x != nil {
+		return x.IndexOffset
+	}
+	return 0
+}
+
+type Header struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Full is set if encoder always writes every quad directions instead of
+	// skipping duplicated values on each direction (except label) for subsequent quads.
+	Full bool `protobuf:"varint,1,opt,name=full,proto3" json:"full,omitempty"`
+	// NotStrict is set if encoder emits WireQuad instead of StrictQuad messages.
+	NotStrict bool `protobuf:"varint,2,opt,name=not_strict,json=notStrict,proto3" json:"not_strict,omitempty"`
+	// Dictionary is set if values are deduplicated using a dictionary shared by all directions.
+	//
+	// Each value that is written in full (not as a ref) is implicitly assigned the next index in the dictionary,
+	// starting from zero, in the order of directions in a quad. Subsequent occurrences of the same value in any
+	// direction are written as a ref to this index, thus a ref never precedes the definition.
+	Dictionary bool `protobuf:"varint,3,opt,name=dictionary,proto3" json:"dictionary,omitempty"`
+	// Ids is set if the file contains IDQuad messages. All other options are ignored in this case.
+	Ids bool `protobuf:"varint,4,opt,name=ids,proto3" json:"ids,omitempty"`
+	// WriterVersion is the version of the module that wrote the file. Optional.
+	WriterVersion string `protobuf:"bytes,5,opt,name=writer_version,json=writerVersion,proto3" json:"writer_version,omitempty"`
+	// Changelog is set if quads may have operations other than add (see WireQuad.op).
+	// Readers that are not aware of operations must not read such files as a plain set of quads.
+	Changelog bool `protobuf:"varint,6,opt,name=changelog,proto3" json:"changelog,omitempty"`
+	// Hashes is set if each quad record contains a hash of the quad.
+	Hashes bool `protobuf:"varint,7,opt,name=hashes,proto3" json:"hashes,omitempty"`
+	// RollingCrc is set if each checkpoint contains a CRC-32C (Castagnoli) of all bytes of the uncompressed stream
+	// preceding the checkpoint record, starting from the file magic. The last record before the block index
+	// (or the end of the file) is always a checkpoint, thus the whole file is covered.
+	RollingCrc bool `protobuf:"varint,8,opt,name=rolling_crc,json=rollingCrc,proto3" json:"rolling_crc,omitempty"`
+	// SubjectGroups is set if the file may contain subject group records.
+	//
+	// A group record contains quads of a single subject: the subject is set in the record itself, and quads are
+	// listed in the group field without the subject. Quads in a group are decoded exactly as if they were written
+	// as consecutive quad records, including the compaction and dictionary state. Only the group subject follows
+	// the compaction rules of a quad subject: it's omitted if it's the same as the subject of the previous quad.
+	SubjectGroups bool `protobuf:"varint,9,opt,name=subject_groups,json=subjectGroups,proto3" json:"subject_groups,omitempty"`
+	// Sources is set if the file may contain source records.
+	Sources bool `protobuf:"varint,10,opt,name=sources,proto3" json:"sources,omitempty"`
+	// Padding is ignored by readers. It's used to make the header end at a fixed offset, see Options.HeaderPad.
+	// The field may occur more than once.
+	Padding []byte `protobuf:"bytes,11,opt,name=padding,proto3" json:"padding,omitempty"`
+	// LangDictionary is set if language tags of language strings are deduplicated with a tag dictionary.
+	//
+	// The dictionary starts with tags listed in lang_tags, numbered from zero. A language string with a tag
+	// which is not in the dictionary has the tag in the lang field, and the tag is assigned the next number.
+	// A string with a known tag has lang_ref set to its number plus one instead. Tags are assigned numbers in the order
+	// of language strings in the stream: in the order of directions within a quad, followed by the quad version,
+	// and for a subject group, in the order of quads in the group. Each checkpoint resets the dictionary to lang_tags.
+	//
+	// Language strings that are not valid UTF-8 (see Value.BinaryString) don't use the dictionary.
+	LangDictionary bool `protobuf:"varint,12,opt,name=lang_dictionary,json=langDictionary,proto3" json:"lang_dictionary,omitempty"`
+	// LangTags are the tags the dictionary starts with.
+	LangTags []string `protobuf:"bytes,13,rep,name=lang_tags,json=langTags,proto3" json:"lang_tags,omitempty"`
+	// TimeDeltas is set if timestamps may be encoded as a difference from the previous timestamp.
+	//
+	// A timestamp value is either written in full (the time field) or as the time_delta field, which is the number
+	// of nanoseconds since the previous timestamp in the stream, or since time_base for the first one. Timestamps
+	// are ordered the same way as language tags in a dictionary (see lang_dictionary). Each checkpoint resets
+	// the previous timestamp to time_base. If time_base is not set, the first timestamp after the start
+	// of the stream or a checkpoint is written in full.
+	//
+	// References to the value dictionary (see dictionary) are not a part of the sequence.
+	TimeDeltas bool             `protobuf:"varint,14,opt,name=time_deltas,json=timeDeltas,proto3" json:"time_deltas,omitempty"`
+	TimeBase   *Value_Timestamp `protobuf:"bytes,15,opt,name=time_base,json=timeBase,proto3" json:"time_base,omitempty"`
+	// SharedDictionary is set if values refer to a dictionary shared with another file, the primary file.
+	// Such files are written by DualOrderWriter and cannot be read without the primary file.
+	//
+	// The shared dictionary contains distinct values of all quads of the primary file in the order of their
+	// first occurrence, in the order of directions within a quad, numbered from zero. Values are written either
+	// as a ref to this dictionary or in full. Values written in full are not added to the dictionary,
+	// and checkpoints don't reset it. The dictionary field must not be set together with this one.
+	SharedDictionary bool `protobuf:"varint,16,opt,name=shared_dictionary,json=sharedDictionary,proto3" json:"shared_dictionary,omitempty"`
+	// SharedValues is the number of values in the shared dictionary.
+	SharedValues uint64 `protobuf:"varint,17,opt,name=shared_values,json=sharedValues,proto3" json:"shared_values,omitempty"`
+}
+
+func (x *Header) Reset() {
+	*x = Header{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Header) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Header) ProtoMessage() {}
+
+func (x *Header) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Header.ProtoReflect.Descriptor instead.
+func (*Header) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Header) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+func (x *Header) GetNotStrict() bool {
+	if x != nil {
+		return x.NotStrict
+	}
+	return false
+}
+
+func (x *Header) GetDictionary() bool {
+	if x != nil {
+		return x.Dictionary
+	}
+	return false
+}
+
+func (x *Header) GetIds() bool {
+	if x != nil {
+		return x.Ids
+	}
+	return false
+}
+
+func (x *Header) GetWriterVersion() string {
+	if x != nil {
+		return x.WriterVersion
+	}
+	return ""
+}
+
+func (x *Header) GetChangelog() bool {
+	if x != nil {
+		return x.Changelog
+	}
+	return false
+}
+
+func (x *Header) GetHashes() bool {
+	if x != nil {
+		return x.Hashes
+	}
+	return false
+}
+
+func (x *Header) GetRollingCrc() bool {
+	if x != nil {
+		return x.RollingCrc
+	}
+	return false
+}
+
+func (x *Header) GetSubjectGroups() bool {
+	if x != nil {
+		return x.SubjectGroups
+	}
+	return false
+}
+
+func (x *Header) GetSources() bool {
+	if x != nil {
+		return x.Sources
+	}
+	return false
+}
+
+func (x *Header) GetPadding() []byte {
+	if x != nil {
+		return x.Padding
+	}
+	return nil
+}
+
+func (x *Header) GetLangDictionary() bool {
+	if x != nil {
+		return x.LangDictionary
+	}
+	return false
+}
+
+func (x *Header) GetLangTags() []string {
+	if x != nil {
+		return x.LangTags
+	}
+	return nil
+}
+
+func (x *Header) GetTimeDeltas() bool {
+	if x != nil {
+		return x.TimeDeltas
+	}
+	return false
+}
+
+func (x *Header) GetTimeBase() *Value_Timestamp {
+	if x != nil {
+		return x.TimeBase
+	}
+	return nil
+}
+
+func (x *Header) GetSharedDictionary() bool {
+	if x != nil {
+		return x.SharedDictionary
+	}
+	return false
+}
+
+func (x *Header) GetSharedValues() uint64 {
+	if x != nil {
+		return x.SharedValues
+	}
+	return 0
+}
+
+// Cursor is a read position in a pquads stream with the state needed to continue decoding from it,
+// see Reader.Cursor. It's not a part of the file format.
+type Cursor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Version of the cursor encoding.
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Offset of the next record from the start of the stream.
+	Offset uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// CRC-32C of the magic, version and the header record.
+	HeaderCrc uint32 `protobuf:"fixed32,3,opt,name=header_crc,json=headerCrc,proto3" json:"header_crc,omitempty"`
+	// Size and CRC-32C of the record that precedes the offset, with its length prefix.
+	LastSize uint64 `protobuf:"varint,4,opt,name=last_size,json=lastSize,proto3" json:"last_size,omitempty"`
+	LastCrc  uint32 `protobuf:"fixed32,5,opt,name=last_crc,json=lastCrc,proto3" json:"last_crc,omitempty"`
+	// Max record size of the reader.
+	MaxSize uint64 `protobuf:"varint,6,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	// Number of quads read or skipped.
+	Ordinal uint64 `protobuf:"varint,7,opt,name=ordinal,proto3" json:"ordinal,omitempty"`
+	// Values of the previous quad used to decode compacted quads.
+	Subject   *Value `protobuf:"bytes,8,opt,name=subject,proto3" json:"subject,omitempty"`
+	Predicate *Value `protobuf:"bytes,9,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	Object    *Value `protobuf:"bytes,10,opt,name=object,proto3" json:"object,omitempty"`
+	// Dictionary values defined so far.
+	Dict []*Value `protobuf:"bytes,11,rep,name=dict,proto3" json:"dict,omitempty"`
+	// Names of source ids, starting from 1, and the current source name.
+	Sources []string `protobuf:"bytes,12,rep,name=sources,proto3" json:"sources,omitempty"`
+	Source  string   `protobuf:"bytes,13,opt,name=source,proto3" json:"source,omitempty"`
+	// Ordinal of the last checkpoint, if it was read.
+	HasCheckpoint bool   `protobuf:"varint,14,opt,name=has_checkpoint,json=hasCheckpoint,proto3" json:"has_checkpoint,omitempty"`
+	Checkpoint    uint64 `protobuf:"varint,15,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	// Rolling CRC state, if it's verified.
+	Sum            uint32 `protobuf:"fixed32,16,opt,name=sum,proto3" json:"sum,omitempty"`
+	CpSum          uint32 `protobuf:"fixed32,17,opt,name=cp_sum,json=cpSum,proto3" json:"cp_sum,omitempty"`
+	LastCheckpoint bool   `protobuf:"varint,18,opt,name=last_checkpoint,json=lastCheckpoint,proto3" json:"last_checkpoint,omitempty"`
+	// Number of quads left in the subject group, if the cursor is inside of it. The group is the last record.
+	GroupLeft uint64 `protobuf:"varint,19,opt,name=group_left,json=groupLeft,proto3" json:"group_left,omitempty"`
+	// Language tag dictionary, not including tags from the header. If the position is inside a subject group,
+	// it's the dictionary preceding the group record, which is decoded again on resume.
+	LangTags []string `protobuf:"bytes,20,rep,name=lang_tags,json=langTags,proto3" json:"lang_tags,omitempty"`
+	// The previous timestamp, if time deltas are used. Inside a subject group, it's the one preceding the group record.
+	LastTime *Value `protobuf:"bytes,21,opt,name=last_time,json=lastTime,proto3" json:"last_time,omitempty"`
+}
+
+func (x *Cursor) Reset() {
+	*x = Cursor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Cursor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cursor) ProtoMessage() {}
+
+func (x *Cursor) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cursor.ProtoReflect.Descriptor instead.
+func (*Cursor) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Cursor) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Cursor) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Cursor) GetHeaderCrc() uint32 {
+	if x != nil {
+		return x.HeaderCrc
+	}
+	return 0
+}
+
+func (x *Cursor) GetLastSize() uint64 {
+	if x != nil {
+		return x.LastSize
+	}
+	return 0
+}
+
+func (x *Cursor) GetLastCrc() uint32 {
+	if x != nil {
+		return x.LastCrc
+	}
+	return 0
+}
+
+func (x *Cursor) GetMaxSize() uint64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *Cursor) GetOrdinal() uint64 {
+	if x != nil {
+		return x.Ordinal
+	}
+	return 0
+}
+
+func (x *Cursor) GetSubject() *Value {
+	if x != nil {
+		return x.Subject
+	}
+	return nil
+}
+
+func (x *Cursor) GetPredicate() *Value {
+	if x != nil {
+		return x.Predicate
+	}
+	return nil
+}
+
+func (x *Cursor) GetObject() *Value {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *Cursor) GetDict() []*Value {
+	if x != nil {
+		return x.Dict
+	}
+	return nil
+}
+
+func (x *Cursor) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *Cursor) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Cursor) GetHasCheckpoint() bool {
+	if x != nil {
+		return x.HasCheckpoint
+	}
+	return false
+}
+
+func (x *Cursor) GetCheckpoint() uint64 {
+	if x != nil {
+		return x.Checkpoint
+	}
+	return 0
+}
+
+func (x *Cursor) GetSum() uint32 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *Cursor) GetCpSum() uint32 {
+	if x != nil {
+		return x.CpSum
+	}
+	return 0
+}
+
+func (x *Cursor) GetLastCheckpoint() bool {
+	if x != nil {
+		return x.LastCheckpoint
+	}
+	return false
+}
+
+func (x *Cursor) GetGroupLeft() uint64 {
+	if x != nil {
+		return x.GroupLeft
+	}
+	return 0
+}
+
+func (x *Cursor) GetLangTags() []string {
+	if x != nil {
+		return x.LangTags
+	}
+	return nil
+}
+
+func (x *Cursor) GetLastTime() *Value {
+	if x != nil {
+		return x.LastTime
+	}
+	return nil
+}
+
+type StrictQuad_Ref struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Value:
+	//
+	//	*StrictQuad_Ref_BnodeLabel
+	//	*StrictQuad_Ref_Iri
+	//	*StrictQuad_Ref_Ref
+	Value isStrictQuad_Ref_Value `protobuf_oneof:"value"`
+}
+
+func (x *StrictQuad_Ref) Reset() {
+	*x = StrictQuad_Ref{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StrictQuad_Ref) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrictQuad_Ref) ProtoMessage() {}
+
+func (x *StrictQuad_Ref) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrictQuad_Ref.ProtoReflect.Descriptor instead.
+func (*StrictQuad_Ref) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (m *StrictQuad_Ref) GetValue() isStrictQuad_Ref_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *StrictQuad_Ref) GetBnodeLabel() string {
+	if x, ok := x.GetValue().(*StrictQuad_Ref_BnodeLabel); ok {
+		return x.BnodeLabel
+	}
+	return ""
+}
+
+func (x *StrictQuad_Ref) GetIri() string {
+	if x, ok := x.GetValue().(*StrictQuad_Ref_Iri); ok {
+		return x.Iri
+	}
+	return ""
+}
+
+func (x *StrictQuad_Ref) GetRef() uint64 {
+	if x, ok := x.GetValue().(*StrictQuad_Ref_Ref); ok {
+		return x.Ref
+	}
+	return 0
+}
+
+type isStrictQuad_Ref_Value interface {
+	isStrictQuad_Ref_Value()
+}
+
+type StrictQuad_Ref_BnodeLabel struct {
+	BnodeLabel string `protobuf:"bytes,2,opt,name=bnode_label,json=bnodeLabel,proto3,oneof"`
+}
+
+type StrictQuad_Ref_Iri struct {
+	Iri string `protobuf:"bytes,3,opt,name=iri,proto3,oneof"`
+}
+
+type StrictQuad_Ref_Ref struct {
+	// Ref is an index of a value in the dictionary, see Header.dictionary.
+	Ref uint64 `protobuf:"varint,4,opt,name=ref,proto3,oneof"`
+}
+
+func (*StrictQuad_Ref_BnodeLabel) isStrictQuad_Ref_Value() {}
+
+func (*StrictQuad_Ref_Iri) isStrictQuad_Ref_Value() {}
+
+func (*StrictQuad_Ref_Ref) isStrictQuad_Ref_Value() {}
+
+type Value_TypedString struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Type  string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *Value_TypedString) Reset() {
+	*x = Value_TypedString{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value_TypedString) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value_TypedString) ProtoMessage() {}
+
+func (x *Value_TypedString) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value_TypedString.ProtoReflect.Descriptor instead.
+func (*Value_TypedString) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *Value_TypedString) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Value_TypedString) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Value_LangString struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Lang  string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	// LangRef is an index of the language tag in the tag dictionary plus one, see Header.lang_dictionary.
+	// The lang field is empty if it's set.
+	LangRef uint64 `protobuf:"varint,3,opt,name=lang_ref,json=langRef,proto3" json:"lang_ref,omitempty"`
+}
+
+func (x *Value_LangString) Reset() {
+	*x = Value_LangString{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value_LangString) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value_LangString) ProtoMessage() {}
+
+func (x *Value_LangString) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value_LangString.ProtoReflect.Descriptor instead.
+func (*Value_LangString) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 1}
+}
+
+func (x *Value_LangString) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Value_LangString) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *Value_LangString) GetLangRef() uint64 {
+	if x != nil {
+		return x.LangRef
+	}
+	return 0
+}
+
+// BinaryString is a string-based value that is not a valid UTF-8, see Options.PreserveInvalidUTF8.
+type Value_BinaryString struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Kind of the value: 0 - string, 1 - IRI, 2 - blank node, 3 - typed string, 4 - language string.
+	Kind  uint32 `protobuf:"varint,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Type is a datatype IRI of a typed string or a language tag of a language string.
+	Type []byte `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *Value_BinaryString) Reset() {
+	*x = Value_BinaryString{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value_BinaryString) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value_BinaryString) ProtoMessage() {}
+
+func (x *Value_BinaryString) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value_BinaryString.ProtoReflect.Descriptor instead.
+func (*Value_BinaryString) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 2}
+}
+
+func (x *Value_BinaryString) GetKind() uint32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *Value_BinaryString) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Value_BinaryString) GetType() []byte {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+// Encoded is a typed literal encoded by a codec registered for its datatype, see RegisterValueCodec.
+type Value_Encoded struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *Value_Encoded) Reset() {
+	*x = Value_Encoded{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value_Encoded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value_Encoded) ProtoMessage() {}
+
+func (x *Value_Encoded) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value_Encoded.ProtoReflect.Descriptor instead.
+func (*Value_Encoded) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 3}
+}
+
+func (x *Value_Encoded) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Value_Encoded) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// From https://github.com/golang/protobuf/blob/master/ptypes/timestamp/timestamp.proto
+type Value_Timestamp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -882,7 +2050,7 @@ type Value_Timestamp struct {
 func (x *Value_Timestamp) Reset() {
 	*x = Value_Timestamp{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[10]
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -895,7 +2063,7 @@ func (x *Value_Timestamp) String() string {
 func (*Value_Timestamp) ProtoMessage() {}
 
 func (x *Value_Timestamp) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[10]
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -908,7 +2076,7 @@ func (x *Value_Timestamp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Value_Timestamp.ProtoReflect.Descriptor instead.
 func (*Value_Timestamp) Descriptor() ([]byte, []int) {
-	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 2}
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{5, 4}
 }
 
 func (x *Value_Timestamp) GetSeconds() int64 {
@@ -925,6 +2093,63 @@ func (x *Value_Timestamp) GetNanos() int32 {
 	return 0
 }
 
+type BlockIndex_Block struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Offset of the first record of the block from the start of the file.
+	Offset uint64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Ordinal of the first quad in the block.
+	Ordinal uint64 `protobuf:"varint,2,opt,name=ordinal,proto3" json:"ordinal,omitempty"`
+}
+
+func (x *BlockIndex_Block) Reset() {
+	*x = BlockIndex_Block{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockIndex_Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockIndex_Block) ProtoMessage() {}
+
+func (x *BlockIndex_Block) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockIndex_Block.ProtoReflect.Descriptor instead.
+func (*BlockIndex_Block) Descriptor() ([]byte, []int) {
+	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *BlockIndex_Block) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *BlockIndex_Block) GetOrdinal() uint64 {
+	if x != nil {
+		return x.Ordinal
+	}
+	return 0
+}
+
 var File_github_com_cayleygraph_quad_pquads_quads_proto protoreflect.FileDescriptor
 
 var file_github_com_cayleygraph_quad_pquads_quads_proto_rawDesc = []byte{
@@ -951,7 +2176,7 @@ var file_github_com_cayleygraph_quad_pquads_quads_proto_rawDesc = []byte{
 	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2e, 0x0a, 0x0b, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x76,
 	0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75,
 	0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0a, 0x6c, 0x61, 0x62, 0x65, 0x6c,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x08, 0x57, 0x69, 0x72, 0x65, 0x51, 0x75,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xeb, 0x03, 0x0a, 0x08, 0x57, 0x69, 0x72, 0x65, 0x51, 0x75,
 	0x61, 0x64, 0x12, 0x27, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c,
 	0x75, 0x65, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2b, 0x0a, 0x09, 0x70,
@@ -962,75 +2187,250 @@ var file_github_com_cayleygraph_quad_pquads_quads_proto_rawDesc = []byte{
 	0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12,
 	0x23, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
 	0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x6c,
-	0x61, 0x62, 0x65, 0x6c, 0x22, 0x73, 0x0a, 0x0b, 0x57, 0x69, 0x72, 0x65, 0x51, 0x75, 0x61, 0x64,
-	0x52, 0x61, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1c, 0x0a,
-	0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6f, 0x62, 0x6a,
-	0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x96, 0x02, 0x0a, 0x0a, 0x53, 0x74,
-	0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a,
-	0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x71, 0x75, 0x61,
-	0x64, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x2e, 0x52, 0x65,
-	0x66, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x70, 0x72,
-	0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61,
-	0x64, 0x2e, 0x52, 0x65, 0x66, 0x52, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x12, 0x25, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
-	0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e,
-	0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x2e, 0x52, 0x65, 0x66, 0x52, 0x05,
-	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x1a, 0x4b, 0x0a, 0x03, 0x52, 0x65, 0x66, 0x12, 0x21, 0x0a, 0x0b,
+	0x61, 0x62, 0x65, 0x6c, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x28, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x26, 0x0a, 0x06, 0x66, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x46, 0x6f, 0x6f, 0x74,
+	0x65, 0x72, 0x52, 0x06, 0x66, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x27,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e,
+	0x57, 0x69, 0x72, 0x65, 0x51, 0x75, 0x61, 0x64, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x2c, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x66, 0x6c,
+	0x61, 0x67, 0x73, 0x22, 0x73, 0x0a, 0x0b, 0x57, 0x69, 0x72, 0x65, 0x51, 0x75, 0x61, 0x64, 0x52,
+	0x61, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0xeb, 0x04, 0x0a, 0x0a, 0x53, 0x74, 0x72,
+	0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x2e, 0x52, 0x65, 0x66,
+	0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x70, 0x72, 0x65,
+	0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70,
+	0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64,
+	0x2e, 0x52, 0x65, 0x66, 0x52, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12,
+	0x25, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x53,
+	0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x2e, 0x52, 0x65, 0x66, 0x52, 0x05, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x28, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x26, 0x0a, 0x06, 0x66, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x46, 0x6f, 0x6f, 0x74,
+	0x65, 0x72, 0x52, 0x06, 0x66, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x27,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e,
+	0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x66, 0x6c, 0x61, 0x67, 0x73, 0x1a, 0x5f, 0x0a, 0x03, 0x52, 0x65, 0x66, 0x12, 0x21, 0x0a, 0x0b,
 	0x62, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x09, 0x48, 0x00, 0x52, 0x0a, 0x62, 0x6e, 0x6f, 0x64, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12,
 	0x12, 0x0a, 0x03, 0x69, 0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03,
-	0x69, 0x72, 0x69, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x4a, 0x04, 0x08, 0x01,
-	0x10, 0x02, 0x22, 0x75, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x51, 0x75, 0x61, 0x64,
-	0x52, 0x61, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1c, 0x0a,
-	0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6f, 0x62, 0x6a,
-	0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0xfa, 0x03, 0x0a, 0x05, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x48, 0x00, 0x52, 0x03, 0x72, 0x61, 0x77, 0x12, 0x12, 0x0a, 0x03, 0x73, 0x74, 0x72, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x73, 0x74, 0x72, 0x12, 0x12, 0x0a, 0x03, 0x69,
-	0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x69, 0x72, 0x69, 0x12,
-	0x16, 0x0a, 0x05, 0x62, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
-	0x52, 0x05, 0x62, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x79, 0x70, 0x65, 0x64,
-	0x5f, 0x73, 0x74, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x71, 0x75,
-	0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x64, 0x53,
-	0x74, 0x72, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x64, 0x53, 0x74,
-	0x72, 0x12, 0x35, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c,
-	0x75, 0x65, 0x2e, 0x4c, 0x61, 0x6e, 0x67, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52,
-	0x07, 0x6c, 0x61, 0x6e, 0x67, 0x53, 0x74, 0x72, 0x12, 0x12, 0x0a, 0x03, 0x69, 0x6e, 0x74, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x03, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x05,
-	0x66, 0x6c, 0x6f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x05, 0x66,
-	0x6c, 0x6f, 0x61, 0x74, 0x12, 0x1a, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e,
-	0x12, 0x2d, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
-	0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x1a,
-	0x37, 0x0a, 0x0b, 0x54, 0x79, 0x70, 0x65, 0x64, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x14,
-	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x1a, 0x36, 0x0a, 0x0a, 0x4c, 0x61, 0x6e, 0x67,
-	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x6c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x67,
-	0x1a, 0x3b, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a,
-	0x07, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
-	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6e, 0x6f, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6e, 0x61, 0x6e, 0x6f, 0x73, 0x42, 0x07, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x3b, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x12, 0x12, 0x0a, 0x04, 0x66, 0x75, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
-	0x66, 0x75, 0x6c, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x69,
-	0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x53, 0x74, 0x72,
-	0x69, 0x63, 0x74, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x69, 0x72, 0x69, 0x12, 0x12, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x66, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x75, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x51, 0x75, 0x61, 0x64, 0x52, 0x61, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0xd4, 0x06,
+	0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x03, 0x72, 0x61, 0x77, 0x12, 0x12, 0x0a, 0x03, 0x73,
+	0x74, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x73, 0x74, 0x72, 0x12,
+	0x12, 0x0a, 0x03, 0x69, 0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03,
+	0x69, 0x72, 0x69, 0x12, 0x16, 0x0a, 0x05, 0x62, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x62, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x79, 0x70, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x54, 0x79,
+	0x70, 0x65, 0x64, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x08, 0x74, 0x79, 0x70,
+	0x65, 0x64, 0x53, 0x74, 0x72, 0x12, 0x35, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x5f, 0x73, 0x74,
+	0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x4c, 0x61, 0x6e, 0x67, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x61, 0x6e, 0x67, 0x53, 0x74, 0x72, 0x12, 0x12, 0x0a, 0x03,
+	0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x03, 0x69, 0x6e, 0x74,
+	0x12, 0x16, 0x0a, 0x05, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48,
+	0x00, 0x52, 0x05, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x12, 0x1a, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6c,
+	0x65, 0x61, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x62, 0x6f, 0x6f,
+	0x6c, 0x65, 0x61, 0x6e, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x35, 0x0a, 0x07, 0x62, 0x69, 0x6e, 0x5f, 0x73,
+	0x74, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x53, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x53, 0x74, 0x72, 0x12, 0x2d,
+	0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x64, 0x48, 0x00, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x12, 0x1f, 0x0a,
+	0x0a, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x12, 0x48, 0x00, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x21,
+	0x0a, 0x0b, 0x78, 0x73, 0x64, 0x5f, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x78, 0x73, 0x64, 0x42, 0x6f, 0x6f, 0x6c, 0x65, 0x61,
+	0x6e, 0x1a, 0x37, 0x0a, 0x0b, 0x54, 0x79, 0x70, 0x65, 0x64, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x1a, 0x51, 0x0a, 0x0a, 0x4c, 0x61,
+	0x6e, 0x67, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x61,
+	0x6e, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x61, 0x6e, 0x67, 0x52, 0x65, 0x66, 0x1a, 0x4c, 0x0a,
+	0x0c, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x1a, 0x31, 0x0a, 0x07, 0x45,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b,
+	0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6e, 0x6f, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6e, 0x61, 0x6e, 0x6f, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x38, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x10, 0x0a, 0x03,
+	0x63, 0x72, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x07, 0x52, 0x03, 0x63, 0x72, 0x63, 0x22, 0x32,
+	0x0a, 0x0c, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x22, 0x95, 0x01, 0x0a, 0x06, 0x49, 0x44, 0x51, 0x75, 0x61, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x64, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x72, 0x65, 0x64,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x12, 0x25, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x49, 0x44, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x3e, 0x0a, 0x07, 0x49, 0x44,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x23, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xf5, 0x01, 0x0a, 0x0a, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x30, 0x0a, 0x06, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x71, 0x75, 0x61,
+	0x64, 0x73, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x71,
+	0x75, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x12, 0x32, 0x0a, 0x0d, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0c, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x30, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71,
+	0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74,
+	0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x1a, 0x39, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x61, 0x6c, 0x22, 0x2b, 0x0a, 0x06, 0x46, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x06, 0x52, 0x0b, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22,
+	0xb5, 0x04, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x75,
+	0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x66, 0x75, 0x6c, 0x6c, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12,
+	0x25, 0x0a, 0x0e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x6c, 0x6f, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x6c, 0x6f, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x72, 0x6f, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x72, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x72, 0x6f, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x43, 0x72, 0x63, 0x12, 0x25, 0x0a,
+	0x0e, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x70, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x27, 0x0a, 0x0f, 0x6c, 0x61, 0x6e, 0x67,
+	0x5f, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x6c, 0x61, 0x6e, 0x67, 0x44, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72,
+	0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x73, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x73, 0x12,
+	0x34, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f,
+	0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x44, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x72, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x99, 0x05, 0x0a, 0x06, 0x43, 0x75, 0x72, 0x73,
+	0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x63,
+	0x72, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x07, 0x52, 0x09, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x43, 0x72, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x19, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x72, 0x63, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x07, 0x52, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x72, 0x63, 0x12, 0x19, 0x0a, 0x08, 0x6d,
+	0x61, 0x78, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d,
+	0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x6c,
+	0x12, 0x27, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2b, 0x0a, 0x09, 0x70, 0x72, 0x65,
+	0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70,
+	0x71, 0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x09, 0x70, 0x72, 0x65,
+	0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x21, 0x0a,
+	0x04, 0x64, 0x69, 0x63, 0x74, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71,
+	0x75, 0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x04, 0x64, 0x69, 0x63, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x68, 0x61, 0x73, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x68, 0x61, 0x73, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x07, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x12, 0x15, 0x0a, 0x06, 0x63,
+	0x70, 0x5f, 0x73, 0x75, 0x6d, 0x18, 0x11, 0x20, 0x01, 0x28, 0x07, 0x52, 0x05, 0x63, 0x70, 0x53,
+	0x75, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6c, 0x61, 0x73,
+	0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x65, 0x66, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61,
+	0x6e, 0x67, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6c,
+	0x61, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x71, 0x75,
+	0x61, 0x64, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
 	0x6d, 0x2f, 0x63, 0x61, 0x79, 0x6c, 0x65, 0x79, 0x67, 0x72, 0x61, 0x70, 0x68, 0x2f, 0x71, 0x75,
 	0x61, 0x64, 0x2f, 0x70, 0x71, 0x75, 0x61, 0x64, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x33,
@@ -1048,19 +2448,29 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescGZIP() []byte {
 	return file_github_com_cayleygraph_quad_pquads_quads_proto_rawDescData
 }
 
-var file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_github_com_cayleygraph_quad_pquads_quads_proto_goTypes = []interface{}{
-	(*Quad)(nil),              // 0: pquads.Quad
-	(*WireQuad)(nil),          // 1: pquads.WireQuad
-	(*WireQuadRaw)(nil),       // 2: pquads.WireQuadRaw
-	(*StrictQuad)(nil),        // 3: pquads.StrictQuad
-	(*StrictQuadRaw)(nil),     // 4: pquads.StrictQuadRaw
-	(*Value)(nil),             // 5: pquads.Value
-	(*Header)(nil),            // 6: pquads.Header
-	(*StrictQuad_Ref)(nil),    // 7: pquads.StrictQuad.Ref
-	(*Value_TypedString)(nil), // 8: pquads.Value.TypedString
-	(*Value_LangString)(nil),  // 9: pquads.Value.LangString
-	(*Value_Timestamp)(nil),   // 10: pquads.Value.Timestamp
+	(*Quad)(nil),               // 0: pquads.Quad
+	(*WireQuad)(nil),           // 1: pquads.WireQuad
+	(*WireQuadRaw)(nil),        // 2: pquads.WireQuadRaw
+	(*StrictQuad)(nil),         // 3: pquads.StrictQuad
+	(*StrictQuadRaw)(nil),      // 4: pquads.StrictQuadRaw
+	(*Value)(nil),              // 5: pquads.Value
+	(*Checkpoint)(nil),         // 6: pquads.Checkpoint
+	(*SourceRecord)(nil),       // 7: pquads.SourceRecord
+	(*IDQuad)(nil),             // 8: pquads.IDQuad
+	(*IDValue)(nil),            // 9: pquads.IDValue
+	(*BlockIndex)(nil),         // 10: pquads.BlockIndex
+	(*Footer)(nil),             // 11: pquads.Footer
+	(*Header)(nil),             // 12: pquads.Header
+	(*Cursor)(nil),             // 13: pquads.Cursor
+	(*StrictQuad_Ref)(nil),     // 14: pquads.StrictQuad.Ref
+	(*Value_TypedString)(nil),  // 15: pquads.Value.TypedString
+	(*Value_LangString)(nil),   // 16: pquads.Value.LangString
+	(*Value_BinaryString)(nil), // 17: pquads.Value.BinaryString
+	(*Value_Encoded)(nil),      // 18: pquads.Value.Encoded
+	(*Value_Timestamp)(nil),    // 19: pquads.Value.Timestamp
+	(*BlockIndex_Block)(nil),   // 20: pquads.BlockIndex.Block
 }
 var file_github_com_cayleygraph_quad_pquads_quads_proto_depIdxs = []int32{
 	5,  // 0: pquads.Quad.subject_value:type_name -> pquads.Value
@@ -1071,18 +2481,43 @@ var file_github_com_cayleygraph_quad_pquads_quads_proto_depIdxs = []int32{
 	5,  // 5: pquads.WireQuad.predicate:type_name -> pquads.Value
 	5,  // 6: pquads.WireQuad.object:type_name -> pquads.Value
 	5,  // 7: pquads.WireQuad.label:type_name -> pquads.Value
-	7,  // 8: pquads.StrictQuad.subject:type_name -> pquads.StrictQuad.Ref
-	7,  // 9: pquads.StrictQuad.predicate:type_name -> pquads.StrictQuad.Ref
-	5,  // 10: pquads.StrictQuad.object:type_name -> pquads.Value
-	7,  // 11: pquads.StrictQuad.label:type_name -> pquads.StrictQuad.Ref
-	8,  // 12: pquads.Value.typed_str:type_name -> pquads.Value.TypedString
-	9,  // 13: pquads.Value.lang_str:type_name -> pquads.Value.LangString
-	10, // 14: pquads.Value.time:type_name -> pquads.Value.Timestamp
-	15, // [15:15] is the sub-list for method output_type
-	15, // [15:15] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	6,  // 8: pquads.WireQuad.checkpoint:type_name -> pquads.Checkpoint
+	10, // 9: pquads.WireQuad.index:type_name -> pquads.BlockIndex
+	11, // 10: pquads.WireQuad.footer:type_name -> pquads.Footer
+	5,  // 11: pquads.WireQuad.version:type_name -> pquads.Value
+	1,  // 12: pquads.WireQuad.group:type_name -> pquads.WireQuad
+	7,  // 13: pquads.WireQuad.source:type_name -> pquads.SourceRecord
+	14, // 14: pquads.StrictQuad.subject:type_name -> pquads.StrictQuad.Ref
+	14, // 15: pquads.StrictQuad.predicate:type_name -> pquads.StrictQuad.Ref
+	5,  // 16: pquads.StrictQuad.object:type_name -> pquads.Value
+	14, // 17: pquads.StrictQuad.label:type_name -> pquads.StrictQuad.Ref
+	6,  // 18: pquads.StrictQuad.checkpoint:type_name -> pquads.Checkpoint
+	10, // 19: pquads.StrictQuad.index:type_name -> pquads.BlockIndex
+	11, // 20: pquads.StrictQuad.footer:type_name -> pquads.Footer
+	5,  // 21: pquads.StrictQuad.version:type_name -> pquads.Value
+	3,  // 22: pquads.StrictQuad.group:type_name -> pquads.StrictQuad
+	7,  // 23: pquads.StrictQuad.source:type_name -> pquads.SourceRecord
+	15, // 24: pquads.Value.typed_str:type_name -> pquads.Value.TypedString
+	16, // 25: pquads.Value.lang_str:type_name -> pquads.Value.LangString
+	19, // 26: pquads.Value.time:type_name -> pquads.Value.Timestamp
+	17, // 27: pquads.Value.bin_str:type_name -> pquads.Value.BinaryString
+	18, // 28: pquads.Value.codec:type_name -> pquads.Value.Encoded
+	9,  // 29: pquads.IDQuad.value:type_name -> pquads.IDValue
+	5,  // 30: pquads.IDValue.value:type_name -> pquads.Value
+	20, // 31: pquads.BlockIndex.blocks:type_name -> pquads.BlockIndex.Block
+	5,  // 32: pquads.BlockIndex.first_subject:type_name -> pquads.Value
+	5,  // 33: pquads.BlockIndex.last_subject:type_name -> pquads.Value
+	19, // 34: pquads.Header.time_base:type_name -> pquads.Value.Timestamp
+	5,  // 35: pquads.Cursor.subject:type_name -> pquads.Value
+	5,  // 36: pquads.Cursor.predicate:type_name -> pquads.Value
+	5,  // 37: pquads.Cursor.object:type_name -> pquads.Value
+	5,  // 38: pquads.Cursor.dict:type_name -> pquads.Value
+	5,  // 39: pquads.Cursor.last_time:type_name -> pquads.Value
+	40, // [40:40] is the sub-list for method output_type
+	40, // [40:40] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cayleygraph_quad_pquads_quads_proto_init() }
@@ -1164,7 +2599,7 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 			}
 		}
 		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Header); i {
+			switch v := v.(*Checkpoint); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1176,7 +2611,7 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 			}
 		}
 		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StrictQuad_Ref); i {
+			switch v := v.(*SourceRecord); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1188,7 +2623,7 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 			}
 		}
 		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Value_TypedString); i {
+			switch v := v.(*IDQuad); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1200,7 +2635,7 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 			}
 		}
 		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Value_LangString); i {
+			switch v := v.(*IDValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1212,6 +2647,114 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 			}
 		}
 		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockIndex); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Footer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Header); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Cursor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StrictQuad_Ref); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value_TypedString); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value_LangString); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value_BinaryString); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value_Encoded); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Value_Timestamp); i {
 			case 0:
 				return &v.state
@@ -1223,6 +2766,18 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 				return nil
 			}
 		}
+		file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockIndex_Block); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[5].OneofWrappers = []interface{}{
 		(*Value_Raw)(nil),
@@ -1235,10 +2790,16 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 		(*Value_Float)(nil),
 		(*Value_Boolean)(nil),
 		(*Value_Time)(nil),
+		(*Value_Ref)(nil),
+		(*Value_BinStr)(nil),
+		(*Value_Codec)(nil),
+		(*Value_TimeDelta)(nil),
+		(*Value_XsdBoolean)(nil),
 	}
-	file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[7].OneofWrappers = []interface{}{
+	file_github_com_cayleygraph_quad_pquads_quads_proto_msgTypes[14].OneofWrappers = []interface{}{
 		(*StrictQuad_Ref_BnodeLabel)(nil),
 		(*StrictQuad_Ref_Iri)(nil),
+		(*StrictQuad_Ref_Ref)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1246,7 +2807,7 @@ func file_github_com_cayleygraph_quad_pquads_quads_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_cayleygraph_quad_pquads_quads_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
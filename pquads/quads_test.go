@@ -0,0 +1,177 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func makeWireQuad(q quad.Quad) *pquads.WireQuad {
+	return &pquads.WireQuad{
+		Subject: pquads.MakeValue(q.Subject), Predicate: pquads.MakeValue(q.Predicate),
+		Object: pquads.MakeValue(q.Object), Label: pquads.MakeValue(q.Label),
+	}
+}
+
+func makeStrictQuad(q quad.Quad) *pquads.StrictQuad {
+	iri := func(v quad.Value) *pquads.StrictQuad_Ref {
+		return &pquads.StrictQuad_Ref{Value: &pquads.StrictQuad_Ref_Iri{Iri: string(v.(quad.IRI))}}
+	}
+	return &pquads.StrictQuad{
+		Subject: iri(q.Subject), Predicate: iri(q.Predicate),
+		Object: pquads.MakeValue(q.Object), Label: &pquads.StrictQuad_Ref{
+			Value: &pquads.StrictQuad_Ref_BnodeLabel{BnodeLabel: string(q.Label.(quad.BNode))},
+		},
+	}
+}
+
+// nativeValues contains values of all types supported by the wire format.
+var nativeValues = []quad.Value{
+	quad.IRI("http://example.org/iri"),
+	quad.BNode("b1"),
+	quad.String("plain string"),
+	quad.TypedString{Value: "typed", Type: "http://example.org/type"},
+	quad.LangString{Value: "tagged", Lang: "en"},
+	quad.Int(0),
+	quad.Int(-123456789),
+	quad.Float(3.25),
+	quad.Bool(true),
+	quad.Bool(false),
+	quad.Time(time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)),
+}
+
+func TestToNativeRoundTrip(t *testing.T) {
+	for _, v := range nativeValues {
+		pv := pquads.MakeValue(v)
+		data, err := pv.MarshalVT()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dv pquads.Value
+		if err = dv.UnmarshalVT(data); err != nil {
+			t.Fatal(err)
+		}
+		if got := dv.ToNative(); got != v {
+			t.Fatalf("round-trip failed: %#v vs %#v", v, got)
+		}
+	}
+	q := quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String("o"), Label: quad.BNode("l")}
+	var wq pquads.WireQuad
+	wdata, err := makeWireQuad(q).MarshalVT()
+	if err != nil {
+		t.Fatal(err)
+	} else if err = wq.UnmarshalVT(wdata); err != nil {
+		t.Fatal(err)
+	} else if got := wq.ToNative(); got != q {
+		t.Fatalf("round-trip failed: %v vs %v", q, got)
+	}
+	sdata, err := makeStrictQuad(q).MarshalVT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ds pquads.StrictQuad
+	if err = ds.UnmarshalVT(sdata); err != nil {
+		t.Fatal(err)
+	} else if got := ds.ToNative(); got != q {
+		t.Fatalf("round-trip failed: %v vs %v", q, got)
+	}
+}
+
+func TestReadValueTypes(t *testing.T) {
+	ctx := context.Background()
+	var in []quad.Quad
+	for i, v := range nativeValues {
+		in = append(in, quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: v})
+		in = append(in, quad.Quad{Subject: quad.BNode(fmt.Sprint("b", i)), Predicate: quad.IRI("p"), Object: v, Label: quad.IRI("g")})
+	}
+	// values and records that are not decoded directly
+	in = append(in,
+		quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String("invalid \xff")},
+		quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.TypedString{}},
+		quad.Quad{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String("")},
+	)
+	for _, opts := range []pquads.Options{
+		{PreserveInvalidUTF8: true},
+		{PreserveInvalidUTF8: true, Strict: true},
+		{PreserveInvalidUTF8: true, Full: true},
+		{PreserveInvalidUTF8: true, QuadHashes: true},
+		{PreserveInvalidUTF8: true, BlockQuads: 3},
+	} {
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(writeQuads(t, &opts, in)), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%+v: corrupted quads:\n%v\n%v", opts, in, out)
+		}
+	}
+}
+
+func BenchmarkReadQuad(b *testing.B) {
+	ctx := context.Background()
+	in := makeQuads(10000)
+	for _, c := range []struct {
+		name string
+		opts pquads.Options
+	}{
+		{"compact", pquads.Options{}},
+		{"full", pquads.Options{Full: true}},
+		{"strict", pquads.Options{Strict: true, Full: true}},
+	} {
+		data := writeQuads(b, &c.opts, in)
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r := pquads.NewReader(bytes.NewReader(data), 0)
+				for {
+					if _, err := r.ReadQuad(ctx); err == io.EOF {
+						break
+					} else if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkToNative(b *testing.B) {
+	q := quad.Quad{
+		Subject:   quad.IRI("http://example.org/subject"),
+		Predicate: quad.IRI("http://example.org/predicate"),
+		Object:    quad.TypedString{Value: "object", Type: "http://example.org/type"},
+		Label:     quad.BNode("graph"),
+	}
+	b.Run("wire", func(b *testing.B) {
+		m := makeWireQuad(q)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = m.ToNative()
+		}
+	})
+	b.Run("strict", func(b *testing.B) {
+		m := makeStrictQuad(q)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = m.ToNative()
+		}
+	})
+	b.Run("values", func(b *testing.B) {
+		vals := make([]*pquads.Value, len(nativeValues))
+		for i, v := range nativeValues {
+			vals[i] = pquads.MakeValue(v)
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, v := range vals {
+				_ = v.ToNative()
+			}
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package pquads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RecordType is a type of a structural record of a pquads file.
+type RecordType int
+
+const (
+	// RecordHeader is the header with file options. It's always the first record, and the only one
+	// for which Record.Header is set.
+	RecordHeader = RecordType(iota)
+	// RecordQuad is a single quad. Values omitted by the compaction or replaced by dictionary references
+	// are not resolved, since it depends on previous records.
+	RecordQuad
+	// RecordCheckpoint resets the compaction and dictionary state. See Writer.Checkpoint.
+	RecordCheckpoint
+	// RecordIndex is the block index, see Options.BlockQuads.
+	RecordIndex
+	// RecordFooter is the last record of files with a block index. It points to the index record.
+	RecordFooter
+	// RecordGroup contains quads of a single subject, see Writer.WriteSubjectGroup.
+	RecordGroup
+	// RecordSource sets the source of subsequent quads, see Writer.SetSource.
+	RecordSource
+)
+
+var recordTypeNames = []string{"header", "quad", "checkpoint", "index", "footer", "group", "source"}
+
+func (t RecordType) String() string {
+	if t < 0 || int(t) >= len(recordTypeNames) {
+		return fmt.Sprintf("RecordType(%d)", int(t))
+	}
+	return recordTypeNames[t]
+}
+
+// Record is a structural record of a pquads file.
+//
+// Records other than the header are decoded into Wire or Strict message, depending on the Strict option
+// of the file; see the message fields for the contents of each record type.
+type Record struct {
+	Type RecordType
+	// Offset of the record in the uncompressed stream, including the length prefix.
+	Offset int64
+	// Size of the record in the stream, including the length prefix.
+	Size int
+	// Raw is the encoded record, without the length prefix. It's only valid until the next call to ReadRecord,
+	// and it's not set for the header.
+	Raw []byte
+
+	Header *Header     // set for RecordHeader
+	Wire   *WireQuad   // set for other records of files without the Strict option
+	Strict *StrictQuad // set for other records of files with the Strict option
+}
+
+// RecordReader reads all structural records of a pquads file, including the header and records that
+// are not quads, without interpreting them. It's intended for tools that inspect, repair or convert files.
+type RecordReader struct {
+	r      *Reader
+	header *Header
+	off    int64 // offset of the next record
+	err    error
+}
+
+// NewRecordReader creates a reader for records of a pquads file. See NewReader for the description of maxSize.
+// Compressed files are decompressed transparently, and offsets refer to the decompressed stream.
+func NewRecordReader(r io.Reader, maxSize int) *RecordReader {
+	qr, h := openReader(r, maxSize)
+	rr := &RecordReader{r: qr, header: h, err: qr.err, off: int64(len(magic) + 4)}
+	if rr.err == nil && h.Ids {
+		rr.err = errors.New("pquads file contains id-based quads")
+	}
+	return rr
+}
+
+// ReadRecord reads the next record. The first record is always the header. It returns io.EOF at the end of the file.
+func (r *RecordReader) ReadRecord(ctx context.Context) (Record, error) {
+	if r.err != nil {
+		return Record{}, r.err
+	} else if err := ctx.Err(); err != nil {
+		return Record{}, err
+	}
+	if h := r.header; h != nil {
+		r.header = nil
+		rec := Record{Type: RecordHeader, Offset: r.off, Size: r.r.hsize, Header: h}
+		r.off += int64(rec.Size)
+		return rec, nil
+	}
+	data, err := r.r.pr.ReadRaw()
+	if err != nil {
+		r.err = err
+		return Record{}, err
+	}
+	rec := Record{Type: RecordQuad, Offset: r.off, Size: protowire.SizeBytes(len(data)), Raw: data}
+	r.off += int64(rec.Size)
+	if r.r.opts.Strict {
+		rec.Strict = &StrictQuad{}
+		err = rec.Strict.UnmarshalVT(data)
+	} else {
+		rec.Wire = &WireQuad{}
+		err = rec.Wire.UnmarshalVT(data)
+	}
+	if err != nil {
+		r.err = fmt.Errorf("record at offset %d: %w", rec.Offset, err)
+		return Record{}, r.err
+	}
+	switch {
+	case hasField(data, checkpointField):
+		rec.Type = RecordCheckpoint
+	case hasField(data, indexField):
+		rec.Type = RecordIndex
+	case hasField(data, footerField):
+		rec.Type = RecordFooter
+	case hasField(data, groupField):
+		rec.Type = RecordGroup
+	case hasField(data, sourceField):
+		rec.Type = RecordSource
+	}
+	return rec, nil
+}
+
+// Close closes the underlying reader.
+func (r *RecordReader) Close() error {
+	return r.r.Close()
+}
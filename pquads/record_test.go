@@ -0,0 +1,85 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestRecordReader(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(30)
+	for _, strict := range []bool{false, true} {
+		opts := &pquads.Options{Strict: strict, BlockQuads: 10, SubjectGroups: true, Sources: true}
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		if err := w.SetSource("a"); err != nil {
+			t.Fatal(err)
+		} else if _, err = w.WriteQuads(ctx, quads[:20]); err != nil {
+			t.Fatal(err)
+		} else if err = w.WriteSubjectGroup(ctx, quads[20].Subject, quads[20:30]); err != nil {
+			t.Fatal(err)
+		} else if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+
+		r := pquads.NewRecordReader(bytes.NewReader(data), 0)
+		var (
+			types []pquads.RecordType
+			off   = int64(8)
+			index int64
+		)
+		for {
+			rec, err := r.ReadRecord(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Offset != off {
+				t.Fatalf("unexpected offset of %v record: %d vs %d", rec.Type, rec.Offset, off)
+			}
+			off += int64(rec.Size)
+			switch rec.Type {
+			case pquads.RecordHeader:
+				if rec.Header.NotStrict == strict || !rec.Header.SubjectGroups {
+					t.Fatalf("unexpected header: %v", rec.Header)
+				}
+			case pquads.RecordIndex:
+				index = rec.Offset
+			case pquads.RecordFooter:
+				var foot *pquads.Footer
+				if strict {
+					foot = rec.Strict.Footer
+				} else {
+					foot = rec.Wire.Footer
+				}
+				if int64(foot.IndexOffset) != index {
+					t.Fatalf("unexpected index offset: %d vs %d", foot.IndexOffset, index)
+				}
+			}
+			types = append(types, rec.Type)
+		}
+		if off != int64(len(data)) {
+			t.Fatalf("records don't cover the file: %d vs %d", off, len(data))
+		}
+		q, c := pquads.RecordQuad, pquads.RecordCheckpoint
+		exp := []pquads.RecordType{pquads.RecordHeader, pquads.RecordSource}
+		for i := 0; i < 10; i++ {
+			exp = append(exp, q)
+		}
+		exp = append(exp, c, pquads.RecordSource)
+		for i := 0; i < 10; i++ {
+			exp = append(exp, q)
+		}
+		exp = append(exp, c, pquads.RecordSource, pquads.RecordGroup, pquads.RecordIndex, pquads.RecordFooter)
+		if !reflect.DeepEqual(types, exp) {
+			t.Fatalf("unexpected records:\n%v\nvs\n%v", types, exp)
+		}
+	}
+}
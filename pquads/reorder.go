@@ -0,0 +1,93 @@
+package pquads
+
+import (
+	"container/heap"
+	"context"
+	"io"
+
+	"github.com/cayleygraph/quad"
+)
+
+var _ quad.ReadCloser = (*ReorderReader)(nil)
+
+// ReorderReader is a quad reader that corrects small disorder of the input using a bounded lookahead.
+type ReorderReader struct {
+	r      *Reader
+	window int
+	h      reorderHeap
+	seq    int // number of quads read from the source
+	eof    bool
+	err    error
+}
+
+// NewReorderReader wraps a reader to emit quads in the order defined by cmp, which returns a negative number
+// if a < b, a positive number if a > b and zero if quads are equal. CompareQuads is used if cmp is nil.
+//
+// The reader keeps up to window quads in memory and always emits the smallest of them. Thus, the output
+// is sorted only if no quad in the input is preceded by more than window-1 quads that are greater than it.
+// Quads that are displaced further are emitted out of order. Equal quads are emitted in the input order.
+//
+// Errors of the source are returned as soon as they occur, discarding quads buffered in the window.
+func NewReorderReader(r *Reader, window int, cmp func(a, b quad.Quad) int) *ReorderReader {
+	if window < 1 {
+		window = 1
+	}
+	if cmp == nil {
+		cmp = CompareQuads
+	}
+	return &ReorderReader{r: r, window: window, h: reorderHeap{cmp: cmp}}
+}
+
+func (r *ReorderReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	if r.err != nil {
+		return quad.Quad{}, r.err
+	}
+	for !r.eof && len(r.h.items) < r.window {
+		q, err := r.r.ReadQuad(ctx)
+		if err == io.EOF {
+			r.eof = true
+			break
+		} else if err != nil {
+			r.err = err
+			return quad.Quad{}, err
+		}
+		heap.Push(&r.h, reorderItem{q: q, seq: r.seq})
+		r.seq++
+	}
+	if len(r.h.items) == 0 {
+		return quad.Quad{}, io.EOF
+	}
+	return heap.Pop(&r.h).(reorderItem).q, nil
+}
+
+func (r *ReorderReader) Close() error {
+	return r.r.Close()
+}
+
+type reorderItem struct {
+	q   quad.Quad
+	seq int
+}
+
+// reorderHeap is a min-heap of quads. Ties are broken by the input order.
+type reorderHeap struct {
+	items []reorderItem
+	cmp   func(a, b quad.Quad) int
+}
+
+func (h *reorderHeap) Len() int { return len(h.items) }
+func (h *reorderHeap) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].q, h.items[j].q); c != 0 {
+		return c < 0
+	}
+	return h.items[i].seq < h.items[j].seq
+}
+func (h *reorderHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *reorderHeap) Push(x interface{}) { h.items = append(h.items, x.(reorderItem)) }
+func (h *reorderHeap) Pop() interface{} {
+	last := len(h.items) - 1
+	it := h.items[last]
+	h.items[last] = reorderItem{}
+	h.items = h.items[:last]
+	return it
+}
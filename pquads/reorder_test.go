@@ -0,0 +1,57 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestReorderReader(t *testing.T) {
+	ctx := context.Background()
+	sorted := makeQuads(200)
+	sort.Slice(sorted, func(i, j int) bool { return pquads.CompareQuads(sorted[i], sorted[j]) < 0 })
+
+	// displace quads by at most 4 positions: swap within consecutive chunks of 5
+	rnd := rand.New(rand.NewSource(1))
+	in := append([]quad.Quad{}, sorted...)
+	for i := 0; i < len(in); i += 5 {
+		chunk := in[i : i+5]
+		rnd.Shuffle(len(chunk), func(a, b int) { chunk[a], chunk[b] = chunk[b], chunk[a] })
+	}
+	data := writeQuads(t, nil, in)
+
+	r := pquads.NewReorderReader(pquads.NewReader(bytes.NewReader(data), 0), 5, nil)
+	got, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, sorted) {
+		t.Fatal("quads are not sorted within the window")
+	}
+
+	// the window is too small to fix the disorder, but no quads are lost
+	r = pquads.NewReorderReader(pquads.NewReader(bytes.NewReader(data), 0), 2, nil)
+	got, err = quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(got) != len(in) {
+		t.Fatalf("expected %d quads, got %d", len(in), len(got))
+	} else if sort.SliceIsSorted(got, func(i, j int) bool { return pquads.CompareQuads(got[i], got[j]) < 0 }) {
+		t.Fatal("expected quads to be partially sorted")
+	}
+
+	// custom order: reverse canonical order
+	rev := func(a, b quad.Quad) int { return -pquads.CompareQuads(a, b) }
+	r = pquads.NewReorderReader(pquads.NewReader(bytes.NewReader(writeQuads(t, nil, sorted[:3])), 0), 3, rev)
+	got, err = quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []quad.Quad{sorted[2], sorted[1], sorted[0]}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected order: %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package pquads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RepairReport describes the result of Repair.
+type RepairReport struct {
+	Size      int64 // size of the file before the repair
+	ValidSize int64 // size of the valid prefix, which is the size of the file after the repair
+	Quads     int   // number of quads in the valid prefix
+	// DroppedQuads is the number of quads that were decoded, but removed with the corrupt data: a quad with a hash
+	// mismatch, or quads of a subject group that is not valid as a whole. Other quads in the corrupt data
+	// cannot be counted.
+	DroppedQuads int
+	// Err is the decoding error at the end of the valid prefix, or nil if the file has no corrupt data.
+	Err error
+}
+
+// Removed returns the number of bytes removed from the file.
+func (r RepairReport) Removed() int64 {
+	return r.Size - r.ValidSize
+}
+
+// Repair reads a file, verifying all quads (including their hashes and rolling CRCs, if the file has them),
+// and truncates it after the last quad that is followed by corrupt or partially written data, thus only the prefix
+// that can be read without errors remains. The file handle must also implement Truncate(size int64) error,
+// as *os.File does. See NewReader for the description of maxSize.
+//
+// Repair is destructive: the removed data is lost and cannot be recovered. The file is never written to,
+// and it's not modified at all if it has no corrupt data, or if reading it fails with an I/O error.
+// Records larger than maxSize are treated as corrupt, thus it must not be smaller than the one used by the writer.
+//
+// The file must have a valid header, and it must not be compressed. A block index is removed with the data
+// that follows the last valid quad, but data after a valid footer is removed without touching the index.
+// It requires an exclusive handle: the file must not be accessed by anyone else during the repair.
+func Repair(rws io.ReadWriteSeeker, maxSize int) (RepairReport, error) {
+	tr, ok := rws.(interface {
+		Truncate(size int64) error
+	})
+	if !ok {
+		return RepairReport{}, errors.New("file doesn't support truncation")
+	}
+	size, err := rws.Seek(0, io.SeekEnd)
+	if err != nil {
+		return RepairReport{}, err
+	} else if _, err = rws.Seek(0, io.SeekStart); err != nil {
+		return RepairReport{}, err
+	}
+	f := &repairFile{r: rws}
+	r := NewReader(f, maxSize)
+	defer r.Close()
+	if f.err != nil {
+		return RepairReport{}, f.err
+	} else if r.err != nil {
+		return RepairReport{}, fmt.Errorf("cannot read header: %w", r.err)
+	} else if r.zr != nil {
+		return RepairReport{}, errors.New("compressed files cannot be repaired")
+	}
+	r.VerifyHashes(true)
+	rep := RepairReport{Size: size, ValidSize: r.off}
+	ctx := context.Background()
+	for {
+		_, _, err := r.ReadChange(ctx)
+		if err == io.EOF {
+			// everything up to the end or to the footer is valid
+			rep.ValidSize, rep.Err = r.off, r.trailing
+			break
+		} else if err != nil {
+			rep.Err = err
+			break
+		}
+		if len(r.wgroup) == 0 && len(r.sgroup) == 0 {
+			rep.ValidSize, rep.Quads = r.off, r.n
+		}
+	}
+	if f.err != nil {
+		return RepairReport{}, f.err
+	}
+	if r.err == io.EOF {
+		rep.Quads = r.n
+	} else {
+		rep.DroppedQuads = r.n - rep.Quads
+	}
+	if rep.ValidSize < size {
+		err = tr.Truncate(rep.ValidSize)
+	}
+	return rep, err
+}
+
+// repairFile remembers the first I/O error of a file, which is not a result of corrupt data.
+type repairFile struct {
+	r   io.Reader
+	err error
+}
+
+func (f *repairFile) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err != nil && err != io.EOF && f.err == nil {
+		f.err = err
+	}
+	return n, err
+}
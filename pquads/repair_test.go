@@ -0,0 +1,88 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func repairFile(t *testing.T, data []byte) (pquads.RepairReport, []byte, error) {
+	path := filepath.Join(t.TempDir(), "quads.pq")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := pquads.Repair(f, 0)
+	f.Close()
+	out, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+	return rep, out, err
+}
+
+func TestRepair(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(100)
+	for _, opts := range []pquads.Options{
+		{},
+		{Dictionary: true, QuadHashes: true},
+		{RollingCRC: true, BlockQuads: 10},
+	} {
+		good := writeQuads(t, &opts, in)
+		corrupt := append([]byte{}, good...)
+		corrupt[len(corrupt)/2] ^= 0xff
+		for _, c := range []struct {
+			name  string
+			data  []byte
+			clean bool
+		}{
+			{name: "clean", data: good, clean: true},
+			{name: "partial", data: good[:len(good)-3]},
+			{name: "garbage", data: append(append([]byte{}, good...), 0xff, 0xff, 0xff, 0x01)},
+			{name: "corrupt", data: corrupt},
+		} {
+			rep, data, err := repairFile(t, c.data)
+			if err != nil {
+				t.Fatalf("%+v, %s: %v", opts, c.name, err)
+			} else if rep.Size != int64(len(c.data)) || rep.ValidSize != int64(len(data)) ||
+				rep.Removed() != int64(len(c.data)-len(data)) {
+				t.Fatalf("%+v, %s: unexpected report: %+v (size %d)", opts, c.name, rep, len(data))
+			} else if c.clean != (rep.Err == nil) || c.clean != (rep.Removed() == 0) {
+				t.Fatalf("%+v, %s: unexpected report: %+v", opts, c.name, rep)
+			} else if !bytes.Equal(data, c.data[:len(data)]) {
+				t.Fatalf("%+v, %s: the valid prefix was modified", opts, c.name)
+			}
+			out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(data), 0))
+			if err != nil {
+				t.Fatalf("%+v, %s: %v", opts, c.name, err)
+			} else if len(out) != rep.Quads || !reflect.DeepEqual(out, in[:len(out)]) {
+				t.Fatalf("%+v, %s: wrong quads after repair: %d vs %d", opts, c.name, len(out), rep.Quads)
+			}
+			if c.name == "partial" && opts.BlockQuads == 0 && rep.Quads != len(in)-1 {
+				t.Fatalf("%+v: only the last quad must be removed: %+v", opts, rep)
+			}
+		}
+	}
+}
+
+func TestRepairErrors(t *testing.T) {
+	data := writeQuads(t, &pquads.Options{Compress: true}, makeQuads(10))
+	if _, out, err := repairFile(t, data[:len(data)-3]); err == nil {
+		t.Fatal("expected an error for a compressed file")
+	} else if !bytes.Equal(out, data[:len(data)-3]) {
+		t.Fatal("compressed file was modified")
+	}
+	if _, _, err := repairFile(t, []byte("not a pquads file")); err == nil {
+		t.Fatal("expected an error for an invalid header")
+	}
+}
@@ -0,0 +1,82 @@
+package pquads
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Rewrite reads all quads from a pquads file and writes them to dst with new options.
+// Source options and compression are detected from the file. See NewReader for the description of srcMaxSize.
+//
+// Changelog operations, quad versions and flags are preserved, thus newOpts must enable Changelog if the source
+// contains deletes.
+// Sources of quads are preserved if newOpts enables Sources.
+// It returns the number of quads written.
+func Rewrite(dst io.Writer, src io.Reader, srcMaxSize int, newOpts *Options) (int, error) {
+	r := NewReader(src, srcMaxSize)
+	defer r.Close()
+	w := NewWriter(dst, newOpts)
+	n, err := copyChanges(w, r)
+	if err != nil {
+		return n, err
+	} else if err = w.Close(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// Rechunk reads all quads from a pquads file and writes them to dst split into blocks of targetBlockQuads quads
+// (see Options.BlockQuads). If the file is compressed, the compression is also restarted at each block
+// (see Options.CompressResetQuads). Zero targetBlockQuads writes the file without blocks and the block index.
+//
+// All other options stored in the file are preserved, while explicit checkpoints of the source are not.
+// Subject groups are written as separate quads. See NewReader for the description of srcMaxSize.
+// It returns the number of quads and blocks written.
+func Rechunk(dst io.Writer, src io.Reader, srcMaxSize, targetBlockQuads int) (int, int, error) {
+	if targetBlockQuads < 0 {
+		return 0, 0, fmt.Errorf("invalid block size: %d", targetBlockQuads)
+	}
+	r := NewReader(src, srcMaxSize)
+	defer r.Close()
+	opts := r.opts
+	opts.BlockQuads = targetBlockQuads
+	if r.zr != nil {
+		opts.Compress = true
+		opts.CompressResetQuads = targetBlockQuads
+	}
+	// values were already validated, and strings with invalid UTF-8 can only come from files that preserve them
+	opts.PreserveInvalidUTF8 = true
+	w := NewWriter(dst, &opts)
+	n, err := copyChanges(w, r)
+	if err != nil {
+		return n, 0, err
+	} else if err = w.Close(); err != nil {
+		return n, 0, err
+	}
+	return n, len(w.blocks), nil
+}
+
+// copyChanges copies all quads with their operations, versions, flags and sources from r to w.
+// It returns the number of quads written.
+func copyChanges(w *Writer, r *Reader) (int, error) {
+	ctx := context.Background()
+	n := 0
+	for {
+		q, op, err := r.ReadChange(ctx)
+		if err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, fmt.Errorf("read quad %d: %w", n, err)
+		}
+		if w.opts.Sources {
+			if err = w.SetSource(r.srcName); err != nil {
+				return n, err
+			}
+		}
+		if err = w.writeChange(ctx, q, op, r.qver, r.flags); err != nil {
+			return n, fmt.Errorf("write quad %d: %w", n, err)
+		}
+		n++
+	}
+}
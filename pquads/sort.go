@@ -0,0 +1,400 @@
+package pquads
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cayleygraph/quad"
+)
+
+// CompareQuads compares quads in canonical order. It returns -1 if a < b, +1 if a > b and 0 if quads are equal.
+//
+// Quads are ordered by subject, predicate, object and label, in that order. Each value is compared by its
+// N-Quads string representation (see quad.StringOf), and a nil label sorts before any other label.
+// This is the same order as defined by quad.ByQuadString.
+func CompareQuads(a, b quad.Quad) int {
+	for _, d := range quad.Directions {
+		if c := strings.Compare(quad.StringOf(a.Get(d)), quad.StringOf(b.Get(d))); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// CompareQuadsByObject compares quads ordered by object, predicate, subject and label, in that order.
+// Values are compared the same way as by CompareQuads. See NewDualOrderWriter.
+func CompareQuadsByObject(a, b quad.Quad) int {
+	for _, d := range []quad.Direction{quad.Object, quad.Predicate, quad.Subject, quad.Label} {
+		if c := strings.Compare(quad.StringOf(a.Get(d)), quad.StringOf(b.Get(d))); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+var errClosed = errors.New("writer is closed")
+
+// DefaultSortMemLimit is the memory limit used by ExternalSortWriter if the limit is not positive.
+const DefaultSortMemLimit = 64 << 20
+
+// mergeFanIn is the maximal number of runs merged at once, which limits the number of open files.
+const mergeFanIn = 64
+
+var _ quad.WriteCloser = (*ExternalSortWriter)(nil)
+
+// ExternalSortWriter is a quad writer that sorts all written quads in canonical order (see CompareQuads)
+// using a bounded amount of memory.
+//
+// Quads are buffered in memory until the buffer reaches the memory limit, after which the buffer is sorted
+// and spilled to a temporary file (a run). On Close, all runs are merged and the sorted result is written
+// to the destination. At most 64 runs are merged at once: if there are more, groups of runs are first merged
+// into larger temporary runs. Temporary files are removed on Close, even if an error occurs, thus Close must always
+// be called.
+type ExternalSortWriter struct {
+	dst   io.Writer
+	dir   string
+	limit int64
+	opts  Options
+	cmp   func(a, b quad.Quad) int // order of quads
+	// newOut creates the writer for the destination
+	newOut func(w io.Writer, opts *Options) *Writer
+
+	buf  []quad.Quad
+	size int64
+	runs []sortRun
+	err  error
+}
+
+type sortRun struct {
+	path string
+	max  int
+}
+
+// NewExternalSortWriter creates a sorting writer that writes quads to dst in canonical order once it's closed.
+//
+// Temporary files are created in tmpDir, or in the default directory for temporary files if it's empty.
+// MemLimit is an approximate limit for the memory used by buffered quads. DefaultSortMemLimit is used
+// if it's not positive.
+func NewExternalSortWriter(dst io.Writer, tmpDir string, memLimit int64, opts *Options) *ExternalSortWriter {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if memLimit <= 0 {
+		memLimit = DefaultSortMemLimit
+	}
+	return &ExternalSortWriter{dst: dst, dir: tmpDir, limit: memLimit, opts: *opts, cmp: CompareQuads, newOut: NewWriter}
+}
+
+func (w *ExternalSortWriter) WriteQuad(ctx context.Context, q quad.Quad) error {
+	if w.err != nil {
+		return w.err
+	} else if !q.IsValid() {
+		return quad.ErrInvalid
+	}
+	w.buf = append(w.buf, q)
+	w.size += quadMemSize(q)
+	if w.size >= w.limit {
+		if w.err = w.spill(ctx); w.err != nil {
+			w.cleanup()
+		}
+	}
+	return w.err
+}
+
+func (w *ExternalSortWriter) WriteQuads(ctx context.Context, buf []quad.Quad) (int, error) {
+	for i, q := range buf {
+		if err := w.WriteQuad(ctx, q); err != nil {
+			return i, err
+		}
+	}
+	return len(buf), nil
+}
+
+func (w *ExternalSortWriter) sortBuffer() {
+	sort.SliceStable(w.buf, func(i, j int) bool {
+		return w.cmp(w.buf[i], w.buf[j]) < 0
+	})
+}
+
+// spill sorts the buffer and writes it to a new temporary file.
+func (w *ExternalSortWriter) spill(ctx context.Context) error {
+	w.sortBuffer()
+	f, err := os.CreateTemp(w.dir, "pquads-sort-*")
+	if err != nil {
+		return err
+	}
+	w.runs = append(w.runs, sortRun{path: f.Name()})
+	bw := bufio.NewWriter(f)
+	qw := NewWriter(bw, nil)
+	_, err = qw.WriteQuads(ctx, w.buf)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return err
+	}
+	w.runs[len(w.runs)-1].max = qw.MaxSize()
+	for i := range w.buf {
+		w.buf[i] = quad.Quad{}
+	}
+	w.buf, w.size = w.buf[:0], 0
+	return nil
+}
+
+func (w *ExternalSortWriter) cleanup() {
+	for _, r := range w.runs {
+		os.Remove(r.path)
+	}
+	w.runs = nil
+}
+
+// Close sorts all quads and writes them to the destination. It removes all temporary files.
+func (w *ExternalSortWriter) Close() error {
+	defer w.cleanup()
+	if w.err != nil {
+		return w.err
+	}
+	w.err = errClosed
+	ctx := context.Background()
+	out := w.newOut(w.dst, &w.opts)
+	if len(w.runs) == 0 {
+		w.sortBuffer()
+		if _, err := out.WriteQuads(ctx, w.buf); err != nil {
+			return err
+		}
+		w.buf = nil
+		return out.Close()
+	}
+	if len(w.buf) != 0 {
+		if err := w.spill(ctx); err != nil {
+			return err
+		}
+	}
+	w.buf = nil
+	if err := w.reduceRuns(ctx); err != nil {
+		return err
+	} else if err = w.merge(ctx, w.runs, out); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// reduceRuns merges groups of consecutive runs into new runs, until they can be merged at once.
+func (w *ExternalSortWriter) reduceRuns(ctx context.Context) error {
+	for len(w.runs) > mergeFanIn {
+		var next []sortRun
+		for len(w.runs) != 0 {
+			n := mergeFanIn
+			if n > len(w.runs) {
+				n = len(w.runs)
+			}
+			group := w.runs[:n]
+			if n == 1 {
+				next = append(next, group[0])
+			} else {
+				run, err := w.mergeRun(ctx, group)
+				if err != nil {
+					// keep all remaining runs for the cleanup
+					w.runs = append(next, w.runs...)
+					return err
+				}
+				for _, r := range group {
+					os.Remove(r.path)
+				}
+				next = append(next, run)
+			}
+			w.runs = w.runs[n:]
+		}
+		w.runs = next
+	}
+	return nil
+}
+
+// mergeRun merges runs into a new temporary run.
+func (w *ExternalSortWriter) mergeRun(ctx context.Context, runs []sortRun) (sortRun, error) {
+	f, err := os.CreateTemp(w.dir, "pquads-sort-*")
+	if err != nil {
+		return sortRun{}, err
+	}
+	bw := bufio.NewWriter(f)
+	qw := NewWriter(bw, nil)
+	err = w.merge(ctx, runs, qw)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return sortRun{}, err
+	}
+	return sortRun{path: f.Name(), max: qw.MaxSize()}, nil
+}
+
+// merge performs a k-way merge of runs.
+func (w *ExternalSortWriter) merge(ctx context.Context, runs []sortRun, out *Writer) error {
+	h := make(mergeHeap, 0, len(runs))
+	defer func() {
+		for _, it := range h {
+			it.r.Close()
+		}
+	}()
+	for i, run := range runs {
+		f, err := os.Open(run.path)
+		if err != nil {
+			return err
+		}
+		r := NewReader(f, run.max)
+		r.SetCloser(f)
+		it := &mergeItem{r: r, ind: i, cmp: w.cmp}
+		if err = it.next(ctx); err == io.EOF {
+			r.Close()
+			continue
+		} else if err != nil {
+			r.Close()
+			return err
+		}
+		h = append(h, it)
+	}
+	heap.Init(&h)
+	for len(h) != 0 {
+		it := h[0]
+		if err := out.WriteQuad(ctx, it.q); err != nil {
+			return err
+		}
+		if err := it.next(ctx); err == io.EOF {
+			heap.Pop(&h)
+			it.r.Close()
+		} else if err != nil {
+			return err
+		} else {
+			heap.Fix(&h, 0)
+		}
+	}
+	return nil
+}
+
+type mergeItem struct {
+	q   quad.Quad
+	r   *Reader
+	ind int
+	cmp func(a, b quad.Quad) int
+}
+
+func (it *mergeItem) next(ctx context.Context) (err error) {
+	it.q, err = it.r.ReadQuad(ctx)
+	return err
+}
+
+// mergeHeap is a min-heap of merge sources, ordered by the current quad.
+// Ties are broken by the source index to keep the merge stable.
+type mergeHeap []*mergeItem
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if c := h[i].cmp(h[i].q, h[j].q); c != 0 {
+		return c < 0
+	}
+	return h[i].ind < h[j].ind
+}
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(*mergeItem)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// quadMemSize returns an approximate size of the memory used by a quad.
+func quadMemSize(q quad.Quad) int64 {
+	const overhead = 4 * 16 // interface headers
+	n := int64(overhead)
+	for _, d := range quad.Directions {
+		n += valueMemSize(q.Get(d))
+	}
+	return n
+}
+
+func valueMemSize(v quad.Value) int64 {
+	const header = 16 // string header
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case quad.IRI:
+		return header + int64(len(v))
+	case quad.BNode:
+		return header + int64(len(v))
+	case quad.String:
+		return header + int64(len(v))
+	case quad.TypedString:
+		return 2*header + int64(len(v.Value)+len(v.Type))
+	case quad.LangString:
+		return 2*header + int64(len(v.Value)+len(v.Lang))
+	}
+	return header
+}
+
+// OrderError is returned by a reader created with NewSortedUniqueReader if input quads are out of order or duplicated.
+type OrderError struct {
+	Ordinal int // zero-based index of the offending quad in the stream
+	Prev    quad.Quad
+	Quad    quad.Quad
+}
+
+func (e *OrderError) Error() string {
+	if CompareQuads(e.Prev, e.Quad) == 0 {
+		return fmt.Sprintf("quad %d: duplicate quad: %v", e.Ordinal, e.Quad)
+	}
+	return fmt.Sprintf("quad %d: quad is out of order: %v after %v", e.Ordinal, e.Quad, e.Prev)
+}
+
+var _ quad.ReadCloser = (*SortedUniqueReader)(nil)
+
+// SortedUniqueReader is a quad reader that verifies that input quads are sorted and unique.
+type SortedUniqueReader struct {
+	r    *Reader
+	prev quad.Quad
+	n    int
+	err  error
+}
+
+// NewSortedUniqueReader wraps a reader to check that each quad is strictly greater than the previous one
+// in canonical order (see CompareQuads), which is the order produced by ExternalSortWriter.
+//
+// At the first violation it returns an *OrderError and fails all subsequent reads with the same error.
+func NewSortedUniqueReader(r *Reader) *SortedUniqueReader {
+	return &SortedUniqueReader{r: r}
+}
+
+func (r *SortedUniqueReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	if r.err != nil {
+		return quad.Quad{}, r.err
+	}
+	q, err := r.r.ReadQuad(ctx)
+	if err != nil {
+		return q, err
+	}
+	if r.n != 0 && CompareQuads(r.prev, q) >= 0 {
+		r.err = &OrderError{Ordinal: r.n, Prev: r.prev, Quad: q}
+		return quad.Quad{}, r.err
+	}
+	r.prev = q
+	r.n++
+	return q, nil
+}
+
+func (r *SortedUniqueReader) Close() error {
+	return r.r.Close()
+}
@@ -0,0 +1,117 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func shuffledQuads(n int) []quad.Quad {
+	quads := makeQuads(n)
+	rand.New(rand.NewSource(1)).Shuffle(len(quads), func(i, j int) {
+		quads[i], quads[j] = quads[j], quads[i]
+	})
+	return quads
+}
+
+func TestExternalSortWriter(t *testing.T) {
+	ctx := context.Background()
+	in := shuffledQuads(2000)
+	exp := make([]quad.Quad, len(in))
+	copy(exp, in)
+	sort.Sort(quad.ByQuadString(exp))
+
+	// a limit of one byte spills every quad, thus runs are merged in several passes
+	for _, limit := range []int64{1 << 30, 4 << 10, 1, 0} {
+		dir := t.TempDir()
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewExternalSortWriter(buf, dir, limit, &pquads.Options{Strict: true})
+		if _, err := w.WriteQuads(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Fatalf("temporary files were not removed: %d", len(files))
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(exp, out) {
+			t.Fatalf("unexpected order with limit %d", limit)
+		}
+	}
+}
+
+type failWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failWriter) Write(p []byte) (int, error) { return 0, errWrite }
+
+func TestExternalSortWriterCleanup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	w := pquads.NewExternalSortWriter(failWriter{}, dir, 4<<10, nil)
+	if _, err := w.WriteQuads(ctx, shuffledQuads(1000)); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) == 0 {
+		t.Fatal("expected temporary runs")
+	}
+	if err := w.Close(); !errors.Is(err, errWrite) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("temporary files were not removed: %d", len(files))
+	}
+}
+
+func TestSortedUniqueReader(t *testing.T) {
+	ctx := context.Background()
+	sorted := makeQuads(100)
+	sort.Sort(quad.ByQuadString(sorted))
+
+	r := pquads.NewSortedUniqueReader(pquads.NewReader(bytes.NewReader(writeQuads(t, nil, sorted)), 0))
+	out, err := quad.ReadAll(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(sorted, out) {
+		t.Fatal("corrupted quads")
+	}
+
+	for _, c := range []struct {
+		name string
+		i    int // index of a quad to replace
+		j    int // index of a replacement
+	}{
+		{"duplicate", 51, 50},
+		{"unsorted", 50, 90}, // quad 51 is less than the replacement
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			in := make([]quad.Quad, len(sorted))
+			copy(in, sorted)
+			in[c.i] = sorted[c.j]
+			r := pquads.NewSortedUniqueReader(pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0))
+			_, err := quad.ReadAll(ctx, r)
+			var e *pquads.OrderError
+			if !errors.As(err, &e) {
+				t.Fatalf("expected order error, got: %v", err)
+			}
+			const exp = 51
+			if e.Ordinal != exp || e.Quad != in[exp] || e.Prev != in[exp-1] {
+				t.Fatalf("unexpected error: %v", e)
+			}
+		})
+	}
+}
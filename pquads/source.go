@@ -0,0 +1,30 @@
+package pquads
+
+import (
+	"context"
+	"io"
+
+	"github.com/cayleygraph/quad"
+)
+
+// Source is a pull-based stream of quads.
+//
+// Pull returns the next quad and more=true, if the quad was read. At the end of the stream it returns
+// a zero quad, more=false and a nil error. Any other error is returned with more=false.
+type Source interface {
+	Pull(ctx context.Context) (q quad.Quad, more bool, err error)
+}
+
+var _ Source = (*Reader)(nil)
+
+// Pull implements Source. It's the same as ReadQuad, except that io.EOF is reported as more=false with a nil error.
+// Errors are returned as-is, and the stream should not be pulled after an error.
+func (r *Reader) Pull(ctx context.Context) (quad.Quad, bool, error) {
+	q, err := r.ReadQuad(ctx)
+	if err == io.EOF {
+		return quad.Quad{}, false, nil
+	} else if err != nil {
+		return quad.Quad{}, false, err
+	}
+	return q, true, nil
+}
@@ -0,0 +1,92 @@
+package pquads
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cayleygraph/quad"
+)
+
+// ErrNotStandalone is returned by DecodeStandalone if a record depends on previous records of the stream.
+var ErrNotStandalone = errors.New("pquads record is not self-contained")
+
+// DecodeStandalone decodes a single quad record, as returned by the raw reader, without the state of the stream.
+// Strict must match the Strict option of the file.
+//
+// It's only valid for records of files written with the Full option, or for the first record after a checkpoint:
+// other records may omit values carried over from the previous quad, in which case ErrNotStandalone is returned.
+// Records of files with a dictionary are rejected the same way if they reference values of previous records,
+// as well as timestamps encoded as time deltas and language strings that reference a tag of the tag dictionary.
+// Changelog operations and versions of quads are ignored.
+func DecodeStandalone(msg []byte, strict bool) (quad.Quad, error) {
+	var q quad.Quad
+	if strict {
+		var pq StrictQuad
+		if err := pq.UnmarshalVT(msg); err != nil {
+			return quad.Quad{}, err
+		} else if pq.Checkpoint != nil || pq.Index != nil || pq.Footer != nil || len(pq.Group) != 0 ||
+			pq.Source != nil {
+			return quad.Quad{}, errors.New("pquads record is not a quad")
+		}
+		for _, r := range []*StrictQuad_Ref{pq.Subject, pq.Predicate, pq.Label} {
+			if r == nil {
+				continue
+			} else if _, ok := r.Value.(*StrictQuad_Ref_Ref); ok {
+				return quad.Quad{}, fmt.Errorf("%w: dictionary reference", ErrNotStandalone)
+			}
+		}
+		if isRef(pq.Object) {
+			return quad.Quad{}, fmt.Errorf("%w: dictionary reference", ErrNotStandalone)
+		} else if isTimeDelta(pq.Object) {
+			return quad.Quad{}, fmt.Errorf("%w: time delta", ErrNotStandalone)
+		} else if isLangRef(pq.Object) {
+			return quad.Quad{}, fmt.Errorf("%w: language tag reference", ErrNotStandalone)
+		} else if err := checkCodec(pq.Object); err != nil {
+			return quad.Quad{}, err
+		}
+		q = pq.ToNative()
+	} else {
+		var pq WireQuad
+		if err := pq.UnmarshalVT(msg); err != nil {
+			return quad.Quad{}, err
+		} else if pq.Checkpoint != nil || pq.Index != nil || pq.Footer != nil || len(pq.Group) != 0 ||
+			pq.Source != nil {
+			return quad.Quad{}, errors.New("pquads record is not a quad")
+		}
+		for _, v := range []*Value{pq.Subject, pq.Predicate, pq.Object, pq.Label} {
+			if isRef(v) {
+				return quad.Quad{}, fmt.Errorf("%w: dictionary reference", ErrNotStandalone)
+			} else if isTimeDelta(v) {
+				return quad.Quad{}, fmt.Errorf("%w: time delta", ErrNotStandalone)
+			} else if isLangRef(v) {
+				return quad.Quad{}, fmt.Errorf("%w: language tag reference", ErrNotStandalone)
+			} else if err := checkCodec(v); err != nil {
+				return quad.Quad{}, err
+			}
+		}
+		q = pq.ToNative()
+	}
+	for _, d := range []quad.Direction{quad.Subject, quad.Predicate, quad.Object} {
+		if q.Get(d) == nil {
+			return quad.Quad{}, fmt.Errorf("%w: %v is omitted", ErrNotStandalone, d)
+		}
+	}
+	return q, nil
+}
+
+func isRef(v *Value) bool {
+	if v == nil {
+		return false
+	}
+	_, ok := v.Value.(*Value_Ref)
+	return ok
+}
+
+func isLangRef(v *Value) bool {
+	return v.GetLangStr().GetLangRef() != 0
+}
+
+func isTimeDelta(v *Value) bool {
+	_, ok := v.GetValue().(*Value_TimeDelta)
+	return ok
+}
@@ -0,0 +1,87 @@
+package pquads_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+	"github.com/cayleygraph/quad/pquads/pio"
+)
+
+// rawRecords returns all records of a pquads file after the header.
+func rawRecords(t testing.TB, data []byte) [][]byte {
+	pr := pio.NewReader(bytes.NewReader(data[8:]), pquads.DefaultMaxSize).(pio.RawReader)
+	if _, err := pr.ReadRaw(); err != nil {
+		t.Fatal(err)
+	}
+	var out [][]byte
+	for {
+		rec, err := pr.ReadRaw()
+		if err != nil {
+			break
+		}
+		out = append(out, append([]byte{}, rec...))
+	}
+	return out
+}
+
+func TestDecodeStandalone(t *testing.T) {
+	quads := makeQuads(10)
+	for _, strict := range []bool{false, true} {
+		recs := rawRecords(t, writeQuads(t, &pquads.Options{Full: true, Strict: strict}, quads))
+		if len(recs) != len(quads) {
+			t.Fatalf("expected %d records, got %d", len(quads), len(recs))
+		}
+		// decode in reverse to make sure no state is carried over
+		for i := len(recs) - 1; i >= 0; i-- {
+			q, err := pquads.DecodeStandalone(recs[i], strict)
+			if err != nil {
+				t.Fatal(err)
+			} else if q != quads[i] {
+				t.Fatalf("quad %d: %v vs %v", i, q, quads[i])
+			}
+		}
+
+		recs = rawRecords(t, writeQuads(t, &pquads.Options{Strict: strict}, quads))
+		if q, err := pquads.DecodeStandalone(recs[0], strict); err != nil {
+			t.Fatal(err)
+		} else if q != quads[0] {
+			t.Fatalf("first quad: %v vs %v", q, quads[0])
+		}
+		if _, err := pquads.DecodeStandalone(recs[1], strict); !errors.Is(err, pquads.ErrNotStandalone) {
+			t.Fatalf("expected an error for a compacted record, got: %v", err)
+		}
+
+		// the second quad has the same subject as the first one
+		recs = rawRecords(t, writeQuads(t, &pquads.Options{Full: true, Strict: strict, Dictionary: true}, quads))
+		if _, err := pquads.DecodeStandalone(recs[1], strict); !errors.Is(err, pquads.ErrNotStandalone) {
+			t.Fatalf("expected an error for a dictionary reference, got: %v", err)
+		}
+
+		// the second quad has the same language tag as the first one
+		langs := makeLangQuads(2)
+		langs[1].Object = langs[0].Object
+		recs = rawRecords(t, writeQuads(t, &pquads.Options{Full: true, Strict: strict, LangDictionary: true}, langs))
+		if q, err := pquads.DecodeStandalone(recs[0], strict); err != nil {
+			t.Fatal(err)
+		} else if q != langs[0] {
+			t.Fatalf("first quad: %v vs %v", q, langs[0])
+		}
+		if _, err := pquads.DecodeStandalone(recs[1], strict); !errors.Is(err, pquads.ErrNotStandalone) {
+			t.Fatalf("expected an error for a language tag reference, got: %v", err)
+		}
+
+		// the first timestamp is written in full, and the next ones as deltas
+		times := makeTimeQuads(4)
+		recs = rawRecords(t, writeQuads(t, &pquads.Options{Full: true, Strict: strict, TimeDeltas: true}, times))
+		if q, err := pquads.DecodeStandalone(recs[0], strict); err != nil {
+			t.Fatal(err)
+		} else if q != times[0] {
+			t.Fatalf("first quad: %v vs %v", q, times[0])
+		}
+		if _, err := pquads.DecodeStandalone(recs[1], strict); !errors.Is(err, pquads.ErrNotStandalone) {
+			t.Fatalf("expected an error for a time delta, got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package pquads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/cayleygraph/quad"
+)
+
+// ErrLossyConversion is returned by StructScanner if an object can only be stored into a field with a loss of data.
+var ErrLossyConversion = errors.New("pquads: value doesn't fit into the field")
+
+// StructScanner reads quads grouped by subject and stores objects of each group into struct fields.
+//
+// It relies on the input being sorted (or at least grouped) by subject. If it's not, the same subject will
+// be returned multiple times, once for each run of quads with this subject.
+type StructScanner struct {
+	r      *Reader
+	fields map[quad.IRI]string
+	next   quad.Quad
+	err    error
+}
+
+// NewStructScanner creates a scanner that maps predicates to struct fields.
+//
+// PredicateTags maps predicate IRIs to names of struct fields. Quads with other predicates are ignored.
+func NewStructScanner(r *Reader, predicateTags map[quad.IRI]string) *StructScanner {
+	return &StructScanner{r: r, fields: predicateTags}
+}
+
+// Scan reads all quads of the next subject and stores their objects into dst, which must be a pointer to a struct.
+// It returns the subject of the group, or io.EOF if there are no quads left.
+//
+// The struct is reset to its zero value first, thus fields for missing predicates will have zero values.
+// Slice fields collect all objects for their predicate. For other fields, the object must be unique
+// for the subject, or an error is returned.
+//
+// Objects are stored either directly, if their type is assignable to the field, or after conversion
+// to a closest native Go type (see quad.NativeOf) of the same kind as the field. Numbers are only converted
+// if the value is preserved: floats must be integral to be stored into integer fields, negative numbers can't
+// be stored into unsigned fields, and values must fit into the field width. Floats may lose precision when stored
+// into float32 fields, but not the magnitude. ErrLossyConversion is returned otherwise.
+func (s *StructScanner) Scan(ctx context.Context, dst interface{}) (quad.Value, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a pointer to a struct, got: %T", dst)
+	}
+	rv = rv.Elem()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.next.Subject == nil {
+		if s.next, s.err = s.r.ReadQuad(ctx); s.err != nil {
+			return nil, s.err
+		}
+	}
+	rv.Set(reflect.Zero(rv.Type()))
+	subj := s.next.Subject
+	set := make(map[string]struct{})
+	for s.next.Subject == subj {
+		if err := s.setField(rv, set, s.next); err != nil {
+			s.err = err
+			return subj, err
+		}
+		q, err := s.r.ReadQuad(ctx)
+		if err == io.EOF {
+			s.next = quad.Quad{}
+			s.err = io.EOF
+			break
+		} else if err != nil {
+			s.err = err
+			return subj, err
+		}
+		s.next = q
+	}
+	return subj, nil
+}
+
+func (s *StructScanner) setField(rv reflect.Value, set map[string]struct{}, q quad.Quad) error {
+	iri, ok := q.Predicate.(quad.IRI)
+	if !ok {
+		return nil
+	}
+	name, ok := s.fields[iri]
+	if !ok {
+		return nil
+	}
+	f := rv.FieldByName(name)
+	if !f.IsValid() || !f.CanSet() {
+		return fmt.Errorf("no exported field %q in %v", name, rv.Type())
+	}
+	if f.Kind() == reflect.Slice && f.Type() != reflect.TypeOf([]byte(nil)) {
+		ev := reflect.New(f.Type().Elem()).Elem()
+		if err := setValue(ev, q.Object); err != nil {
+			return fmt.Errorf("field %s: %w", name, err)
+		}
+		f.Set(reflect.Append(f, ev))
+		return nil
+	}
+	if _, ok := set[name]; ok {
+		return fmt.Errorf("field %s: multiple values for %v of %v", name, iri, q.Subject)
+	}
+	set[name] = struct{}{}
+	if err := setValue(f, q.Object); err != nil {
+		return fmt.Errorf("field %s: %w", name, err)
+	}
+	return nil
+}
+
+func setValue(f reflect.Value, v quad.Value) error {
+	ft := f.Type()
+	if rv := reflect.ValueOf(v); rv.Type().AssignableTo(ft) {
+		f.Set(rv)
+		return nil
+	}
+	nv := reflect.ValueOf(quad.NativeOf(v))
+	if nv.Type().AssignableTo(ft) {
+		f.Set(nv)
+		return nil
+	}
+	if sameKind(nv.Kind(), ft.Kind()) && nv.Type().ConvertibleTo(ft) {
+		if !lossless(nv, ft) {
+			return fmt.Errorf("%w: %v into %v", ErrLossyConversion, v, ft)
+		}
+		f.Set(nv.Convert(ft))
+		return nil
+	}
+	return fmt.Errorf("cannot store %T into %v", v, ft)
+}
+
+// sameKind checks if values of two kinds can be converted without changing their meaning.
+func sameKind(a, b reflect.Kind) bool {
+	kind := func(k reflect.Kind) int {
+		switch k {
+		case reflect.String:
+			return 1
+		case reflect.Bool:
+			return 2
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return 3
+		}
+		return 0
+	}
+	ka := kind(a)
+	return ka != 0 && ka == kind(b)
+}
+
+// lossless checks if a value of one of the kinds accepted by sameKind keeps its meaning when converted to t.
+func lossless(v reflect.Value, t reflect.Type) bool {
+	switch {
+	case v.Kind() == reflect.String || v.Kind() == reflect.Bool:
+		return true
+	case isFloat(v.Kind()) && isFloat(t.Kind()):
+		// only the precision may be lost
+		return math.IsInf(v.Convert(t).Float(), 0) == math.IsInf(v.Float(), 0)
+	case isFloat(v.Kind()) && !floatInRange(v.Float(), isUnsigned(t.Kind())):
+		// the result of the conversion is implementation-dependent
+		return false
+	}
+	c := v.Convert(t)
+	return c.Convert(v.Type()).Interface() == v.Interface() && isNegative(c) == isNegative(v)
+}
+
+// floatInRange checks if a float can be converted to a 64 bit integer.
+func floatInRange(f float64, unsigned bool) bool {
+	if unsigned {
+		return f >= 0 && f < 1<<64
+	}
+	return f >= -(1<<63) && f < 1<<63
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNegative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package pquads
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads/pio"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// SubjectReader reads only subjects of quads together with offsets of their records, and allows to fetch
+// complete quads by offset later. It's intended for selective joins on subject, where only a small fraction
+// of quads needs to be decoded.
+//
+// It requires a seekable (io.ReaderAt) uncompressed source, and doesn't support files with a dictionary,
+// time deltas or subject groups.
+type SubjectReader struct {
+	ra      io.ReaderAt
+	maxSize int
+	opts    Options
+	pr      pio.RawReader
+	off     int64 // offset of the next record
+	s       quad.Value
+	err     error
+}
+
+// NewSubjectReader creates a subject reader for a pquads file of a given size.
+// See NewReader for the description of maxSize.
+func NewSubjectReader(ra io.ReaderAt, size int64, maxSize int) (*SubjectReader, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	hr, _ := openReader(io.NewSectionReader(ra, 0, size), maxSize)
+	if hr.err != nil {
+		return nil, hr.err
+	} else if hr.zr != nil {
+		return nil, errors.New("compressed pquads files are not seekable")
+	} else if hr.opts.Dictionary || hr.opts.LangDictionary {
+		return nil, errors.New("pquads files with a dictionary are not supported")
+	} else if hr.opts.TimeDeltas {
+		return nil, errors.New("pquads files with time deltas are not supported")
+	} else if hr.opts.SubjectGroups {
+		return nil, errors.New("pquads files with subject groups are not supported")
+	}
+	// header record follows the magic and version
+	off := int64(len(magic) + 4)
+	hsize, err := frameSize(ra, off)
+	if err != nil {
+		return nil, err
+	}
+	off += hsize
+	return &SubjectReader{
+		ra: ra, maxSize: maxSize, opts: hr.opts, off: off,
+		pr: pio.NewReader(io.NewSectionReader(ra, off, size-off), maxSize).(pio.RawReader),
+	}, nil
+}
+
+// frameSize returns the size of the record at a given offset, including the length prefix.
+func frameSize(ra io.ReaderAt, off int64) (int64, error) {
+	var buf [binary.MaxVarintLen64]byte
+	n, err := ra.ReadAt(buf[:], off)
+	if n == 0 && err != nil {
+		return 0, err
+	}
+	sz, k := binary.Uvarint(buf[:n])
+	if k <= 0 {
+		return 0, fmt.Errorf("invalid record length at offset %d", off)
+	}
+	return int64(k) + int64(sz), nil
+}
+
+// ReadSubject returns the subject of the next quad and the offset of the quad record, which can be passed
+// to FetchQuadAt. It returns io.EOF if no quads are left.
+//
+// Only the subject is decoded; other values of the quad are skipped.
+func (r *SubjectReader) ReadSubject() (quad.Value, int64, error) {
+	for {
+		if r.err != nil {
+			return nil, 0, r.err
+		}
+		var data []byte
+		if data, r.err = r.pr.ReadRaw(); r.err != nil {
+			return nil, 0, r.err
+		}
+		off := r.off
+		r.off += int64(protowire.SizeBytes(len(data)))
+		if hasField(data, indexField) || hasField(data, footerField) || hasField(data, sourceField) {
+			continue
+		} else if hasField(data, checkpointField) {
+			r.s = nil
+			continue
+		}
+		sdata, ok := fieldBytes(data, 1)
+		if !ok {
+			if r.s == nil {
+				r.err = fmt.Errorf("no subject for quad at offset %d", off)
+				return nil, 0, r.err
+			}
+			return r.s, off, nil
+		}
+		var v quad.Value
+		if r.opts.Strict {
+			var ref StrictQuad_Ref
+			if r.err = ref.UnmarshalVT(sdata); r.err != nil {
+				return nil, 0, r.err
+			}
+			v = ref.ToNative()
+		} else {
+			var pv Value
+			if r.err = pv.UnmarshalVT(sdata); r.err != nil {
+				return nil, 0, r.err
+			} else if v, r.err = nativeValue(&pv); r.err != nil {
+				return nil, 0, r.err
+			}
+		}
+		r.s = v
+		return v, off, nil
+	}
+}
+
+// FetchQuadAt reads and decodes a complete quad at a given offset, as returned by ReadSubject.
+//
+// Since values of compacted quads are carried over from previous quads, it requires a file written
+// with the Full option. It can be called concurrently with other calls to FetchQuadAt, if the source allows it.
+func (r *SubjectReader) FetchQuadAt(off int64) (quad.Quad, error) {
+	if !r.opts.Full {
+		return quad.Quad{}, errors.New("fetching quads by offset requires a file written with the Full option")
+	}
+	size, err := frameSize(r.ra, off)
+	if err != nil {
+		return quad.Quad{}, err
+	} else if size > int64(binary.MaxVarintLen64+r.maxSize) {
+		return quad.Quad{}, io.ErrShortBuffer
+	}
+	var data []byte
+	if data, err = pio.NewReader(io.NewSectionReader(r.ra, off, size), r.maxSize).(pio.RawReader).ReadRaw(); err != nil {
+		return quad.Quad{}, err
+	}
+	q, err := DecodeStandalone(data, r.opts.Strict)
+	if err != nil {
+		return quad.Quad{}, fmt.Errorf("no quad at offset %d: %w", off, err)
+	}
+	return q, nil
+}
+
+// fieldBytes returns the value of the last occurrence of a length-delimited field.
+func fieldBytes(data []byte, num protowire.Number) ([]byte, bool) {
+	var (
+		out []byte
+		ok  bool
+	)
+	for len(data) > 0 {
+		n, typ, l := protowire.ConsumeTag(data)
+		if l < 0 {
+			return nil, false
+		}
+		data = data[l:]
+		if n == num && typ == protowire.BytesType {
+			out, l = protowire.ConsumeBytes(data)
+			ok = l >= 0
+		} else {
+			l = protowire.ConsumeFieldValue(n, typ, data)
+		}
+		if l < 0 {
+			return nil, false
+		}
+		data = data[l:]
+	}
+	return out, ok
+}
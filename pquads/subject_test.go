@@ -0,0 +1,56 @@
+package pquads_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestSubjectReader(t *testing.T) {
+	in := makeQuads(55)
+	for _, opts := range []pquads.Options{
+		{},
+		{Full: true},
+		{Full: true, Strict: true},
+		{Full: true, BlockQuads: 10},
+	} {
+		data := writeQuads(t, &opts, in)
+		r, err := pquads.NewSubjectReader(bytes.NewReader(data), int64(len(data)), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var offs []int64
+		for i, q := range in {
+			s, off, err := r.ReadSubject()
+			if err != nil {
+				t.Fatal(err)
+			} else if s != q.Subject {
+				t.Fatalf("%+v: unexpected subject %d: %v", opts, i, s)
+			}
+			offs = append(offs, off)
+		}
+		if _, _, err = r.ReadSubject(); err == nil {
+			t.Fatal("expected EOF")
+		}
+		if !opts.Full {
+			if _, err = r.FetchQuadAt(offs[1]); err == nil {
+				t.Fatal("expected an error for compacted file")
+			}
+			continue
+		}
+		for _, i := range []int{54, 0, 11, 10} {
+			q, err := r.FetchQuadAt(offs[i])
+			if err != nil {
+				t.Fatal(err)
+			} else if q != in[i] {
+				t.Fatalf("%+v: unexpected quad %d: %v", opts, i, q)
+			}
+		}
+	}
+
+	data := writeQuads(t, &pquads.Options{Dictionary: true}, in)
+	if _, err := pquads.NewSubjectReader(bytes.NewReader(data), int64(len(data)), 0); err == nil {
+		t.Fatal("expected an error for a file with dictionary")
+	}
+}
@@ -0,0 +1,56 @@
+package pquads
+
+import (
+	"fmt"
+	"io"
+)
+
+// NewTeeWriter creates a writer that encodes quads once and writes the encoded stream to both primary
+// and secondary writers, for example to a file and to a hash. Writing fails if either of the writes fails.
+//
+// On Close, both writers are flushed if they implement Flush() error (like bufio.Writer), and then closed
+// if they implement io.Closer. Both writers are flushed and closed even if one of them fails.
+func NewTeeWriter(primary, secondary io.Writer, opts *Options) *Writer {
+	t := &teeWriter{w: [2]io.Writer{primary, secondary}}
+	w := NewWriter(t, opts)
+	w.SetCloser(t)
+	return w
+}
+
+var teeNames = [2]string{"primary", "secondary"}
+
+type teeWriter struct {
+	w [2]io.Writer
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	for i, w := range t.w {
+		n, err := w.Write(p)
+		if err == nil && n != len(p) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s writer: %w", teeNames[i], err)
+		}
+	}
+	return len(p), nil
+}
+
+func (t *teeWriter) Close() error {
+	var last error
+	for i, w := range t.w {
+		var err error
+		if f, ok := w.(interface{ Flush() error }); ok {
+			err = f.Flush()
+		}
+		if c, ok := w.(io.Closer); ok {
+			if err2 := c.Close(); err == nil {
+				err = err2
+			}
+		}
+		if err != nil && last == nil {
+			last = fmt.Errorf("%s writer: %w", teeNames[i], err)
+		}
+	}
+	return last
+}
@@ -0,0 +1,39 @@
+package pquads_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestTeeWriter(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(100)
+	opts := &pquads.Options{Compress: true}
+	exp := writeQuads(t, opts, quads)
+
+	var file bytes.Buffer
+	bw := bufio.NewWriter(&file)
+	h := sha256.New()
+	w := pquads.NewTeeWriter(bw, h, opts)
+	if _, err := w.WriteQuads(ctx, quads); err != nil {
+		t.Fatal(err)
+	} else if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(file.Bytes(), exp) {
+		t.Fatal("unexpected output of the primary writer")
+	} else if sum := sha256.Sum256(exp); !bytes.Equal(h.Sum(nil), sum[:]) {
+		t.Fatal("unexpected hash of the output")
+	}
+
+	w = pquads.NewTeeWriter(&file, failWriter{}, nil)
+	if _, err := w.WriteQuads(ctx, quads); !errors.Is(err, errWrite) {
+		t.Fatalf("expected an error from the secondary writer, got: %v", err)
+	}
+}
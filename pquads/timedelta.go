@@ -0,0 +1,86 @@
+package pquads
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errTimeDelta = errors.New("time delta cannot be decoded without the previous timestamp")
+
+// resetTime resets the previous timestamp to the base time from the header, as done by the checkpoint.
+func (w *Writer) resetTime() {
+	w.lastTime, w.hasTime = w.opts.TimeBase, !w.opts.TimeBase.IsZero()
+}
+
+// encodeTime replaces a timestamp with a difference from the previous one, if it fits into the delta.
+func (w *Writer) encodeTime(v *Value) error {
+	ts, ok := v.GetValue().(*Value_Time)
+	if !ok {
+		return nil
+	}
+	t := timestampTime(ts.Time)
+	if w.hasTime {
+		// Sub saturates on overflow, thus check that the delta restores the timestamp exactly
+		if d := t.Sub(w.lastTime); w.lastTime.Add(d).Equal(t) {
+			v.Value = &Value_TimeDelta{int64(d)}
+		}
+	}
+	w.lastTime, w.hasTime = t, true
+	return nil
+}
+
+// resetTime resets the previous timestamp to the base time from the header, as done by the checkpoint.
+func (r *Reader) resetTime() {
+	r.lastTime, r.hasTime = r.opts.TimeBase, !r.opts.TimeBase.IsZero()
+}
+
+// decodeValue restores a time delta of a single value and checks that an encoded value can be decoded.
+func (r *Reader) decodeValue(v *Value) error {
+	if err := r.decodeTime(v); err != nil {
+		return err
+	} else if err = checkCodec(v); err != nil {
+		return fmt.Errorf("quad %d: %w", r.n, err)
+	}
+	return nil
+}
+
+// decodeTime restores a timestamp encoded as a difference from the previous one.
+func (r *Reader) decodeTime(v *Value) error {
+	switch tv := v.GetValue().(type) {
+	case *Value_Time:
+		if r.opts.TimeDeltas {
+			r.lastTime, r.hasTime = timestampTime(tv.Time), true
+		}
+	case *Value_TimeDelta:
+		if !r.opts.TimeDeltas {
+			return fmt.Errorf("unexpected time delta at quad %d", r.n)
+		} else if !r.hasTime {
+			return fmt.Errorf("time delta without a previous timestamp at quad %d", r.n)
+		}
+		t := r.lastTime.Add(time.Duration(tv.TimeDelta))
+		v.Value = &Value_Time{makeTimestamp(t)}
+		r.lastTime = t
+	}
+	return nil
+}
+
+func timestampTime(ts *Value_Timestamp) time.Time {
+	if ts == nil {
+		return time.Unix(0, 0).UTC()
+	}
+	return time.Unix(ts.Seconds, int64(ts.Nanos)).UTC()
+}
+
+func makeTimestamp(t time.Time) *Value_Timestamp {
+	seconds := t.Unix()
+	return &Value_Timestamp{Seconds: seconds, Nanos: int32(t.Sub(time.Unix(seconds, 0)))}
+}
+
+// timeBase converts the base time from the header, which is the zero time if it's not set.
+func timeBase(ts *Value_Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return timestampTime(ts)
+}
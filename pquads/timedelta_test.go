@@ -0,0 +1,192 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+var timeBase = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// makeTimeQuads makes quads with an increasing timestamp each, with nanoseconds and a few repeated values.
+func makeTimeQuads(n int) []quad.Quad {
+	quads := make([]quad.Quad, 0, n)
+	for i := 0; i < n; i++ {
+		t := timeBase.Add(time.Duration(i/2) * 1234567891)
+		quads = append(quads, quad.Quad{
+			Subject:   quad.IRI(fmt.Sprintf("s%d", i/10)),
+			Predicate: quad.IRI(fmt.Sprintf("p%d", i%2)),
+			Object:    quad.Time(t),
+		})
+	}
+	return quads
+}
+
+func TestTimeDeltas(t *testing.T) {
+	ctx := context.Background()
+	in := makeTimeQuads(100)
+	// far from the others, thus written in full
+	in[50].Object = quad.Time(time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC))
+	in[51].Object = quad.Time(time.Date(2262, 1, 1, 0, 0, 0, 0, time.UTC))
+	for _, opts := range []pquads.Options{
+		{TimeDeltas: true},
+		{TimeDeltas: true, TimeBase: timeBase},
+		{TimeDeltas: true, TimeBase: timeBase.In(time.FixedZone("UTC+3", 3*3600))},
+		{TimeDeltas: true, Dictionary: true},
+		{TimeDeltas: true, Strict: true, BlockQuads: 7},
+		{TimeDeltas: true, Full: true, QuadHashes: true, RollingCRC: true},
+	} {
+		data := writeQuads(t, &opts, in)
+		popts := opts
+		popts.TimeDeltas, popts.TimeBase = false, time.Time{}
+		if plain := len(writeQuads(t, &popts, in)); len(data) >= plain {
+			t.Errorf("%+v: file is not smaller: %d vs %d", opts, len(data), plain)
+		}
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.VerifyHashes(true)
+		out, err := quad.ReadAll(ctx, r)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		} else if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%+v: wrong quads:\n%v", opts, out)
+		}
+	}
+}
+
+func TestTimeDeltasWire(t *testing.T) {
+	ctx := context.Background()
+	in := makeTimeQuads(4)
+	data := writeQuads(t, &pquads.Options{Full: true, TimeDeltas: true, TimeBase: timeBase}, in)
+	rr := pquads.NewRecordReader(bytes.NewReader(data), 0)
+	defer rr.Close()
+	rec, err := rr.ReadRecord(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if h := rec.Header; !h.TimeDeltas || h.TimeBase.GetSeconds() != timeBase.Unix() {
+		t.Fatalf("unexpected header: %v", h)
+	}
+	exp := []int64{0, 0, 1234567891, 0}
+	for i := 0; ; i++ {
+		rec, err := rr.ReadRecord(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		} else if rec.Type != pquads.RecordQuad {
+			continue
+		}
+		v, ok := rec.Wire.Object.GetValue().(*pquads.Value_TimeDelta)
+		if !ok || v.TimeDelta != exp[i] {
+			t.Fatalf("quad %d: unexpected value: %v", i, rec.Wire.Object)
+		}
+	}
+}
+
+func TestTimeDeltasVersions(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(20)
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, &pquads.Options{Changelog: true, TimeDeltas: true})
+	for i, q := range in {
+		if err := w.WriteQuadVersioned(ctx, q, quad.Time(timeBase.Add(time.Duration(i)*time.Millisecond))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := pquads.NewReader(bytes.NewReader(buf.Bytes()), 0)
+	for i := range in {
+		q, ver, err := r.ReadQuadVersioned(ctx)
+		if err != nil {
+			t.Fatal(err)
+		} else if q != in[i] {
+			t.Fatalf("quad %d: unexpected quad: %v", i, q)
+		} else if exp := quad.Time(timeBase.Add(time.Duration(i) * time.Millisecond)); ver != exp {
+			t.Fatalf("quad %d: unexpected version: %v", i, ver)
+		}
+	}
+}
+
+func TestTimeDeltasBlocks(t *testing.T) {
+	ctx := context.Background()
+	in := makeTimeQuads(50)
+	for _, opts := range []pquads.Options{
+		{TimeDeltas: true, BlockQuads: 10},
+		{TimeDeltas: true, TimeBase: timeBase, BlockQuads: 10},
+	} {
+		data := writeQuads(t, &opts, in)
+		// each block starts from the base time
+		ir, err := pquads.NewIndexedReader(bytes.NewReader(data), int64(len(data)), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, b := range []int{0, 2} {
+			out, err := quad.ReadAll(ctx, ir.Block(b))
+			if err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			} else if i := ir.BlockOrdinal(b); !reflect.DeepEqual(out, in[i:i+len(out)]) {
+				t.Fatalf("%+v: wrong quads in block %d:\n%v", opts, b, out)
+			}
+		}
+	}
+}
+
+func TestTimeDeltasCursor(t *testing.T) {
+	ctx := context.Background()
+	in := makeTimeQuads(100)
+	langs := makeLangQuads(100)
+	for i := 0; i < len(in); i += 3 {
+		in[i].Object = langs[i].Object
+	}
+	for _, opts := range []pquads.Options{
+		{TimeDeltas: true},
+		{TimeDeltas: true, TimeBase: timeBase, SubjectGroups: true},
+		{TimeDeltas: true, LangDictionary: true, SubjectGroups: true, Strict: true},
+	} {
+		data := writeCursorFile(t, &opts, in)
+		for _, k := range []int{0, 1, 13, 35} {
+			r := pquads.NewReader(bytes.NewReader(data), 0)
+			var out []quad.Quad
+			for len(out) < k {
+				q, err := r.ReadQuad(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				out = append(out, q)
+			}
+			cur, err := r.Cursor()
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err = pquads.ResumeReader(bytes.NewReader(data), cur)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rest, err := quad.ReadAll(ctx, r)
+			if err != nil {
+				t.Fatalf("%+v, %d: %v", opts, k, err)
+			}
+			if out = append(out, rest...); !reflect.DeepEqual(out, in) {
+				t.Fatalf("%+v, %d: wrong quads after resuming:\n%v", opts, k, out)
+			}
+		}
+	}
+}
+
+func TestTimeDeltaUnmarshalValue(t *testing.T) {
+	data, err := (&pquads.Value{Value: &pquads.Value_TimeDelta{TimeDelta: 5}}).MarshalVT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pquads.UnmarshalValue(context.Background(), data); err == nil {
+		t.Fatal("expected an error for a time delta")
+	}
+}
@@ -0,0 +1,97 @@
+package pquads
+
+import (
+	"math/bits"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Phases of reading a quad, as measured by Reader.Timings:
+//
+//   - IO is the time spent reading the source stream. For compressed streams it's the compressed data.
+//   - Decompress is the time spent in gzip decompression, excluding the I/O it triggers.
+//   - Decode is the time spent unmarshaling protobuf records.
+//   - Convert is the time spent converting records to quads: resolving dictionary references,
+//     decoding typed values, filling compacted values and verifying hashes.
+//
+// IO and Decompress are measured per read call to the underlying stream, thus the number of observations
+// depends on buffering rather than on the number of quads. Decode and Convert are measured per record and per quad.
+
+// histBuckets is the number of buckets in the Histogram.
+const histBuckets = 32
+
+// Histogram is a distribution of durations of a single phase.
+type Histogram struct {
+	Count int64         // number of observations
+	Total time.Duration // sum of all observations
+	Max   time.Duration // the longest observation
+	// Buckets are counts of observations with exponentially growing bounds: bucket i counts durations
+	// shorter than 2^i nanoseconds which are not counted by the previous buckets. The last bucket is unbounded.
+	Buckets [histBuckets]int64
+}
+
+func (h *Histogram) add(d time.Duration) {
+	h.Count++
+	h.Total += d
+	if d > h.Max {
+		h.Max = d
+	}
+	i := 0
+	if d > 0 {
+		i = bits.Len64(uint64(d))
+	}
+	if i >= histBuckets {
+		i = histBuckets - 1
+	}
+	h.Buckets[i]++
+}
+
+// Mean returns the average duration of the phase.
+func (h *Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Total / time.Duration(h.Count)
+}
+
+// PhaseTimings are distributions of time spent in each phase of reading.
+type PhaseTimings struct {
+	IO         Histogram
+	Decompress Histogram
+	Decode     Histogram
+	Convert    Histogram
+}
+
+// RecordTimings enables or disables recording of the time spent in each phase of reading. See Timings.
+//
+// Durations are measured with the monotonic clock. The overhead is a few clock reads per quad,
+// which is usually small compared to decoding.
+func (r *Reader) RecordTimings(on bool) {
+	if !on {
+		r.src.tm = nil
+	} else if r.src.tm == nil {
+		r.src.tm = &PhaseTimings{}
+	}
+}
+
+// Timings returns the time spent in each phase of reading since the recording was enabled with RecordTimings.
+// It must not be called concurrently with reads.
+func (r *Reader) Timings() PhaseTimings {
+	if r.src.tm == nil {
+		return PhaseTimings{}
+	}
+	return *r.src.tm
+}
+
+// unmarshal decodes a record read by readRaw, measuring the decoding time if timings are enabled.
+func (r *Reader) unmarshal(data []byte, m proto.Message) error {
+	tm := r.src.tm
+	if tm == nil {
+		return proto.Unmarshal(data, m)
+	}
+	start := time.Now()
+	err := proto.Unmarshal(data, m)
+	tm.Decode.add(time.Since(start))
+	return err
+}
@@ -0,0 +1,43 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestReaderTimings(t *testing.T) {
+	// large enough to not fit into the read buffer when opening the reader
+	quads := makeQuads(5000)
+	for _, compress := range []bool{false, true} {
+		data := writeQuads(t, &pquads.Options{Compress: compress}, quads)
+		r := pquads.NewReader(bytes.NewReader(data), 0)
+		r.RecordTimings(true)
+		got, err := quad.ReadAll(context.Background(), r)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(got) != len(quads) {
+			t.Fatalf("expected %d quads, got %d", len(quads), len(got))
+		}
+		tm := r.Timings()
+		if tm.Convert.Count != int64(len(quads)) {
+			t.Fatalf("expected %d conversions, got %d", len(quads), tm.Convert.Count)
+		} else if tm.Decode.Count != int64(len(quads)) {
+			t.Fatalf("expected %d records decoded, got %d", len(quads), tm.Decode.Count)
+		} else if tm.IO.Count == 0 {
+			t.Fatal("no I/O recorded")
+		} else if compress != (tm.Decompress.Count != 0) {
+			t.Fatalf("unexpected decompression count for compress=%v: %d", compress, tm.Decompress.Count)
+		}
+		var n int64
+		for _, c := range tm.Convert.Buckets {
+			n += c
+		}
+		if n != tm.Convert.Count {
+			t.Fatalf("buckets don't add up: %d vs %d", n, tm.Convert.Count)
+		}
+	}
+}
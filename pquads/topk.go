@@ -0,0 +1,136 @@
+package pquads
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cayleygraph/quad"
+)
+
+// PredCount is an approximate number of occurrences of a predicate.
+type PredCount struct {
+	Predicate quad.Value
+	// Count is an estimated number of quads with this predicate. It never underestimates the true count.
+	Count int64
+	// Err is the maximal overestimation of the count: the true count is in the range [Count-Err, Count].
+	Err int64
+}
+
+// TopPredicates finds approximately k most frequent predicates in a pquads file, using bounded memory.
+// Results are sorted by count in descending order.
+//
+// It uses the Space-Saving algorithm with k counters. For a file with N quads, every predicate that occurs
+// more than N/k times is guaranteed to be in the result, and counts are overestimated by at most N/k
+// (see PredCount.Err for a tighter bound for each predicate). Counts are exact if the file has at most k
+// distinct predicates.
+//
+// Predicates are compared in the encoded form and only the predicate field is decoded, except for files
+// with a dictionary, language tag dictionary, time deltas or subject groups, which are decoded completely.
+// Quads with changelog operations are counted regardless of the operation.
+func TopPredicates(r io.Reader, maxSize, k int) ([]PredCount, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("invalid k: %d", k)
+	}
+	qr := NewReader(r, maxSize)
+	defer qr.Close()
+	if qr.err != nil {
+		return nil, qr.err
+	}
+	s := newSpaceSaving(k)
+	if err := scanPredicates(qr, s.add); err != nil {
+		return nil, err
+	}
+	if scanDecodes(&qr.opts) {
+		return s.result(nil)
+	}
+	return s.result(func(key string) (quad.Value, error) {
+		return decodePredicate(qr.opts.Strict, key)
+	})
+}
+
+type ssCounter struct {
+	key   string
+	val   quad.Value
+	count int64
+	err   int64
+	ind   int // index in the heap
+}
+
+// spaceSaving implements the Space-Saving algorithm for finding frequent items.
+type spaceSaving struct {
+	k     int
+	items map[string]*ssCounter
+	heap  ssHeap // min-heap by count
+}
+
+func newSpaceSaving(k int) *spaceSaving {
+	return &spaceSaving{k: k, items: make(map[string]*ssCounter, k)}
+}
+
+func (s *spaceSaving) add(key string, val quad.Value) {
+	if c, ok := s.items[key]; ok {
+		c.count++
+		heap.Fix(&s.heap, c.ind)
+		return
+	}
+	if len(s.heap) < s.k {
+		c := &ssCounter{key: key, val: val, count: 1}
+		s.items[key] = c
+		heap.Push(&s.heap, c)
+		return
+	}
+	// replace the item with the smallest count
+	c := s.heap[0]
+	delete(s.items, c.key)
+	c.key, c.val = key, val
+	c.err = c.count
+	c.count++
+	s.items[key] = c
+	heap.Fix(&s.heap, 0)
+}
+
+// result returns all counters sorted by count. Decode function is used to convert keys to values, if it's set.
+func (s *spaceSaving) result(decode func(key string) (quad.Value, error)) ([]PredCount, error) {
+	cnt := make([]*ssCounter, len(s.heap))
+	copy(cnt, s.heap)
+	sort.Slice(cnt, func(i, j int) bool {
+		if cnt[i].count != cnt[j].count {
+			return cnt[i].count > cnt[j].count
+		}
+		return cnt[i].key < cnt[j].key
+	})
+	out := make([]PredCount, 0, len(cnt))
+	for _, c := range cnt {
+		v := c.val
+		if decode != nil {
+			var err error
+			if v, err = decode(c.key); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, PredCount{Predicate: v, Count: c.count, Err: c.err})
+	}
+	return out, nil
+}
+
+type ssHeap []*ssCounter
+
+func (h ssHeap) Len() int           { return len(h) }
+func (h ssHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h ssHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].ind, h[j].ind = i, j
+}
+func (h *ssHeap) Push(x interface{}) {
+	c := x.(*ssCounter)
+	c.ind = len(*h)
+	*h = append(*h, c)
+}
+func (h *ssHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
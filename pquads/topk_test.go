@@ -0,0 +1,158 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestTopPredicates(t *testing.T) {
+	counts := map[quad.IRI]int64{"p0": 500, "p1": 300, "p2": 100}
+	var in []quad.Quad
+	for p, n := range counts {
+		for i := int64(0); i < n; i++ {
+			in = append(in, quad.MakeIRI(fmt.Sprintf("s%d", i%7), string(p), fmt.Sprintf("o%d", i), ""))
+		}
+	}
+	for i := 0; i < 100; i++ {
+		p := quad.IRI(fmt.Sprintf("rare%d", i))
+		counts[p] = 1
+		in = append(in, quad.MakeIRI("s", string(p), "o", ""))
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(in), func(i, j int) { in[i], in[j] = in[j], in[i] })
+
+	for _, opts := range []pquads.Options{
+		{},
+		{Strict: true},
+		{Full: true},
+		{Dictionary: true},
+		{BlockQuads: 100},
+	} {
+		data := writeQuads(t, &opts, in)
+		top, err := pquads.TopPredicates(bytes.NewReader(data), 0, 5)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(top) != 5 {
+			t.Fatalf("unexpected number of results: %d", len(top))
+		}
+		// predicates with count > N/k must be found
+		if top[0].Predicate != quad.IRI("p0") || top[1].Predicate != quad.IRI("p1") {
+			t.Fatalf("%+v: unexpected top predicates: %v", opts, top)
+		}
+		for _, c := range top {
+			exp := counts[c.Predicate.(quad.IRI)]
+			if c.Count < exp || c.Count-c.Err > exp || c.Err > int64(len(in)/5) {
+				t.Fatalf("%+v: wrong bounds for %v: %d (err %d), expected %d", opts, c.Predicate, c.Count, c.Err, exp)
+			}
+		}
+
+		top, err = pquads.TopPredicates(bytes.NewReader(data), 0, len(counts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range top {
+			if exp := counts[c.Predicate.(quad.IRI)]; c.Count != exp || c.Err != 0 {
+				t.Fatalf("%+v: expected exact count for %v: %d (err %d) vs %d", opts, c.Predicate, c.Count, c.Err, exp)
+			}
+		}
+	}
+}
+
+func TestCountByPredicate(t *testing.T) {
+	in := makeQuads(100) // predicates p0, p1 and p2 in turn
+	in = append(in, quad.MakeIRI("s", "p1", "o", ""), quad.MakeIRI("s", "p1", "o2", ""))
+	for _, opts := range []pquads.Options{
+		{},
+		{Strict: true},
+		{Full: true},
+		{Dictionary: true},
+		{BlockQuads: 10},
+	} {
+		data := writeQuads(t, &opts, in)
+		for p, exp := range map[quad.IRI]int{"p0": 34, "p1": 35, "p2": 33, "p3": 0} {
+			n, err := pquads.CountByPredicate(bytes.NewReader(data), 0, p)
+			if err != nil {
+				t.Fatal(err)
+			} else if n != exp {
+				t.Fatalf("%+v: expected %d quads with %v, got %d", opts, exp, p, n)
+			}
+		}
+	}
+}
+
+func TestCountByPredicateValueState(t *testing.T) {
+	// predicates that depend on the preceding records when encoded
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	preds := []quad.Value{
+		quad.LangString{Value: "p", Lang: "en"},
+		quad.LangString{Value: "p", Lang: "fr"},
+		quad.Time(t0),
+		quad.Time(t0.Add(time.Second)),
+	}
+	var in []quad.Quad
+	for i := 0; i < 100; i++ {
+		in = append(in, quad.Quad{Subject: quad.IRI(fmt.Sprintf("s%d", i)), Predicate: preds[i%len(preds)], Object: quad.Int(i)})
+	}
+	for _, opts := range []pquads.Options{
+		{LangDictionary: true},
+		{TimeDeltas: true},
+		{LangDictionary: true, TimeDeltas: true, Full: true},
+	} {
+		data := writeQuads(t, &opts, in)
+		for _, p := range preds {
+			n, err := pquads.CountByPredicate(bytes.NewReader(data), 0, p)
+			if err != nil {
+				t.Fatal(err)
+			} else if n != 25 {
+				t.Fatalf("%+v: expected 25 quads with %v, got %d", opts, p, n)
+			}
+		}
+		top, err := pquads.TopPredicates(bytes.NewReader(data), 0, len(preds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range top {
+			if c.Count != 25 || quad.StringOf(c.Predicate) == "" {
+				t.Fatalf("%+v: unexpected count for %v: %d", opts, c.Predicate, c.Count)
+			}
+		}
+	}
+}
+
+func BenchmarkCountByPredicate(b *testing.B) {
+	data := writeQuads(b, nil, makeQuads(100000))
+	b.Run("predicate", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			if _, err := pquads.CountByPredicate(bytes.NewReader(data), 0, quad.IRI("p1")); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("full", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		ctx := context.Background()
+		for i := 0; i < b.N; i++ {
+			r := pquads.NewReader(bytes.NewReader(data), 0)
+			n := 0
+			for {
+				q, err := r.ReadQuad(ctx)
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					b.Fatal(err)
+				}
+				if q.Predicate == quad.IRI("p1") {
+					n++
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package pquads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/cayleygraph/quad"
+)
+
+// TransformParallel reads all quads from src, applies fn to each of them concurrently and writes
+// the results to dst in the original order. Quads for which fn returns false are dropped.
+//
+// Src is read sequentially and at most a few quads per worker are kept in flight. If workers <= 0,
+// GOMAXPROCS workers will be used. Processing stops at the first error (in the stream order), either
+// returned by fn or by the reader or writer. It returns the number of quads written to dst.
+func TransformParallel(dst *Writer, src *Reader, workers int, fn func(quad.Quad) (quad.Quad, bool, error)) (int, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type result struct {
+		q   quad.Quad
+		ok  bool
+		err error
+	}
+	type job struct {
+		i   int
+		q   quad.Quad
+		res chan<- result
+	}
+	jobs := make(chan job)
+	// results of all jobs in the stream order
+	pending := make(chan chan result, 2*workers)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				q, ok, err := fn(j.q)
+				if err != nil {
+					err = fmt.Errorf("transform quad %d: %w", j.i, err)
+				}
+				j.res <- result{q: q, ok: ok, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+		for i := 0; ; i++ {
+			q, err := src.ReadQuad(ctx)
+			if err == io.EOF {
+				return
+			}
+			res := make(chan result, 1)
+			if err != nil {
+				res <- result{err: fmt.Errorf("read quad %d: %w", i, err)}
+			}
+			select {
+			case pending <- res:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			select {
+			case jobs <- job{i: i, q: q, res: res}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	n := 0
+	for res := range pending {
+		r := <-res
+		if r.err != nil {
+			cancel()
+			return n, r.err
+		} else if !r.ok {
+			continue
+		}
+		if err := dst.WriteQuad(ctx, r.q); err != nil {
+			cancel()
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,75 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestTransformParallel(t *testing.T) {
+	ctx := context.Background()
+	in := makeQuads(500)
+	var exp []quad.Quad
+	for i, q := range in {
+		if i%3 == 0 {
+			continue
+		}
+		q.Object = quad.String(strings.ToUpper(string(q.Object.(quad.String))))
+		exp = append(exp, q)
+	}
+	src := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0)
+	buf := bytes.NewBuffer(nil)
+	dst := pquads.NewWriter(buf, nil)
+	n, err := pquads.TransformParallel(dst, src, 8, func(q quad.Quad) (quad.Quad, bool, error) {
+		// shuffle completion order
+		time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+		s := string(q.Object.(quad.String))
+		var ind int
+		for _, c := range strings.TrimPrefix(s, "object ") {
+			ind = ind*10 + int(c-'0')
+		}
+		if ind%3 == 0 {
+			return q, false, nil
+		}
+		q.Object = quad.String(strings.ToUpper(s))
+		return q, true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if n != len(exp) {
+		t.Fatalf("unexpected number of quads: %d vs %d", n, len(exp))
+	}
+	out, err := quad.ReadAll(ctx, pquads.NewReader(buf, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exp, out) {
+		t.Fatal("unexpected quads order")
+	}
+}
+
+func TestTransformParallelError(t *testing.T) {
+	in := makeQuads(500)
+	src := pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0)
+	dst := pquads.NewWriter(bytes.NewBuffer(nil), nil)
+	errFail := errors.New("fail")
+	n, err := pquads.TransformParallel(dst, src, 4, func(q quad.Quad) (quad.Quad, bool, error) {
+		if q == in[100] {
+			return q, false, errFail
+		}
+		return q, true, nil
+	})
+	if !errors.Is(err, errFail) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 100 {
+		t.Fatalf("expected all quads before the error to be written: %d", n)
+	}
+}
@@ -0,0 +1,196 @@
+package pquads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cayleygraph/quad"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TermType is a kind of an RDF term, as reported by PredicateObjectTypeStats.
+type TermType string
+
+const (
+	TermIRI         = TermType("iri")
+	TermBNode       = TermType("bnode")
+	TermString      = TermType("string")
+	TermLangString  = TermType("lang_string")
+	TermTypedString = TermType("typed_string")
+	TermInt         = TermType("int")
+	TermFloat       = TermType("float")
+	TermBool        = TermType("bool")
+	TermTime        = TermType("time")
+	TermOther       = TermType("other")
+)
+
+// TermTypeOf returns the term type of a value.
+func TermTypeOf(v quad.Value) TermType {
+	switch v.(type) {
+	case quad.IRI:
+		return TermIRI
+	case quad.BNode:
+		return TermBNode
+	case quad.String:
+		return TermString
+	case quad.LangString:
+		return TermLangString
+	case quad.TypedString:
+		return TermTypedString
+	case quad.Int:
+		return TermInt
+	case quad.Float:
+		return TermFloat
+	case quad.Bool:
+		return TermBool
+	case quad.Time:
+		return TermTime
+	}
+	return TermOther
+}
+
+// valueTermTypes maps fields of the Value oneof to term types. Other fields need to be decoded.
+var valueTermTypes = map[protowire.Number]TermType{
+	2:  TermString,
+	3:  TermIRI,
+	4:  TermBNode,
+	5:  TermTypedString,
+	6:  TermLangString,
+	7:  TermInt,
+	8:  TermFloat,
+	9:  TermBool,
+	10: TermTime,
+	14: TermTime,        // time delta
+	15: TermTypedString, // compact xsd:boolean
+}
+
+// PredObjType is a combination of a predicate and a term type of the object.
+//
+// It's encoded as text (and thus as a JSON object key) in the form "<predicate> type",
+// with the predicate formatted by quad.StringOf.
+type PredObjType struct {
+	Predicate  string // predicate, as returned by quad.StringOf
+	ObjectType TermType
+}
+
+func (t PredObjType) String() string {
+	return t.Predicate + " " + string(t.ObjectType)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t PredObjType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *PredObjType) UnmarshalText(text []byte) error {
+	s := string(text)
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		return fmt.Errorf("invalid predicate and object type: %q", s)
+	}
+	t.Predicate, t.ObjectType = s[:i], TermType(s[i+1:])
+	return nil
+}
+
+// PredicateObjectTypeStats counts quads for each combination of a predicate and a term type of the object
+// in a pquads file. See NewReader for the description of maxSize.
+//
+// For most files only the predicate and the type of the object value are decoded. Files with a dictionary,
+// language tag dictionary, time deltas or subject groups are decoded completely. Quads with changelog operations
+// are counted regardless of the operation.
+func PredicateObjectTypeStats(r io.Reader, maxSize int) (map[PredObjType]int, error) {
+	qr := NewReader(r, maxSize)
+	defer qr.Close()
+	if qr.err != nil {
+		return nil, qr.err
+	}
+	out := make(map[PredObjType]int)
+	if scanDecodes(&qr.opts) {
+		ctx := context.Background()
+		for {
+			q, _, err := qr.ReadChange(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			out[PredObjType{Predicate: quad.StringOf(q.Predicate), ObjectType: TermTypeOf(q.Object)}]++
+		}
+		return out, nil
+	}
+	type key struct {
+		pred string // encoded predicate
+		typ  TermType
+	}
+	var (
+		cnt  = make(map[key]int)
+		pred string
+		typ  TermType
+	)
+	for i := 0; ; i++ {
+		data, err := qr.pr.ReadRaw()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("quad %d: %w", i, err)
+		}
+		if hasField(data, checkpointField) || hasField(data, indexField) || hasField(data, footerField) {
+			pred, typ = "", ""
+			continue
+		} else if hasField(data, sourceField) {
+			continue
+		}
+		if p, ok := fieldBytes(data, 2); ok {
+			pred = string(p)
+		}
+		if o, ok := fieldBytes(data, 3); ok {
+			if typ, err = objectTermType(o); err != nil {
+				return nil, fmt.Errorf("quad %d: %w", i, err)
+			}
+		}
+		if pred == "" || typ == "" {
+			return nil, fmt.Errorf("quad %d: no predicate or object", i)
+		}
+		cnt[key{pred: pred, typ: typ}]++
+	}
+	for k, n := range cnt {
+		var p quad.Value
+		if qr.opts.Strict {
+			var ref StrictQuad_Ref
+			if err := ref.UnmarshalVT([]byte(k.pred)); err != nil {
+				return nil, err
+			}
+			p = ref.ToNative()
+		} else {
+			var v Value
+			if err := v.UnmarshalVT([]byte(k.pred)); err != nil {
+				return nil, err
+			} else if p, err = nativeValue(&v); err != nil {
+				return nil, err
+			}
+		}
+		out[PredObjType{Predicate: quad.StringOf(p), ObjectType: k.typ}] += n
+	}
+	return out, nil
+}
+
+// objectTermType returns the term type of an encoded value, decoding it only if it's necessary.
+func objectTermType(data []byte) (TermType, error) {
+	if n, _, l := protowire.ConsumeTag(data); l > 0 {
+		if t, ok := valueTermTypes[n]; ok {
+			return t, nil
+		}
+	}
+	var v Value
+	if err := v.UnmarshalVT(data); err != nil {
+		return "", err
+	}
+	qv, err := nativeValue(&v)
+	if err != nil {
+		return "", err
+	}
+	return TermTypeOf(qv), nil
+}
@@ -0,0 +1,90 @@
+package pquads_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestPredicateObjectTypeStats(t *testing.T) {
+	label, age, at := quad.IRI("rdfs:label"), quad.IRI("age"), quad.IRI("at")
+	var quads []quad.Quad
+	for i := 0; i < 10; i++ {
+		s := quad.IRI("s" + string(rune('a'+i)))
+		quads = append(quads, quad.Quad{Subject: s, Predicate: label, Object: quad.LangString{Value: "x", Lang: "en"}})
+		if i%3 == 0 {
+			quads = append(quads, quad.Quad{Subject: s, Predicate: label, Object: quad.String("x")})
+		}
+		quads = append(quads, quad.Quad{Subject: s, Predicate: at, Object: quad.Time(time.Unix(int64(i), 0).UTC())})
+		quads = append(quads, quad.Quad{Subject: s, Predicate: age, Object: quad.Int(i)})
+	}
+	// the same object as the previous quad is omitted by the compaction
+	quads = append(quads, quad.Quad{Subject: quad.IRI("sz"), Predicate: quad.IRI("age2"), Object: quad.Int(9)})
+	exp := map[pquads.PredObjType]int{
+		{Predicate: "<rdfs:label>", ObjectType: pquads.TermLangString}: 10,
+		{Predicate: "<rdfs:label>", ObjectType: pquads.TermString}:     4,
+		{Predicate: "<age>", ObjectType: pquads.TermInt}:               10,
+		{Predicate: "<at>", ObjectType: pquads.TermTime}:               10,
+		{Predicate: "<age2>", ObjectType: pquads.TermInt}:              1,
+	}
+	for _, opts := range []*pquads.Options{
+		nil,
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{TimeDeltas: true},
+		{TimeDeltas: true, Full: true, Strict: true},
+	} {
+		got, err := pquads.PredicateObjectTypeStats(bytes.NewReader(writeQuads(t, opts, quads)), 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(got, exp) {
+			t.Fatalf("%+v: unexpected stats: %v", opts, got)
+		}
+	}
+
+	data, err := json.Marshal(exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[pquads.PredObjType]int
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected stats after JSON round-trip: %s", data)
+	}
+}
+
+func TestPredicateObjectTypeStatsValueState(t *testing.T) {
+	// predicates that depend on the preceding records with a language tag dictionary or time deltas
+	preds := []quad.Value{
+		quad.LangString{Value: "p", Lang: "en"},
+		quad.LangString{Value: "p", Lang: "fr"},
+		quad.Time(time.Unix(100, 0).UTC()),
+		quad.Time(time.Unix(200, 0).UTC()),
+	}
+	var quads []quad.Quad
+	exp := make(map[pquads.PredObjType]int)
+	for i := 0; i < 20; i++ {
+		p := preds[i%len(preds)]
+		quads = append(quads, quad.Quad{Subject: quad.IRI("s"), Predicate: p, Object: quad.Int(i)})
+		exp[pquads.PredObjType{Predicate: quad.StringOf(p), ObjectType: pquads.TermInt}]++
+	}
+	for _, opts := range []*pquads.Options{
+		{LangDictionary: true},
+		{TimeDeltas: true},
+		{LangDictionary: true, TimeDeltas: true, Full: true},
+	} {
+		got, err := pquads.PredicateObjectTypeStats(bytes.NewReader(writeQuads(t, opts, quads)), 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(got, exp) {
+			t.Fatalf("%+v: unexpected stats: %v", opts, got)
+		}
+	}
+}
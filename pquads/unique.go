@@ -0,0 +1,181 @@
+package pquads
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/cayleygraph/quad"
+)
+
+// UniqueMode selects how a reader created with NewUniqueReader tracks values it has seen.
+type UniqueMode int
+
+const (
+	// UniqueExact keeps all seen values in memory and never reports false duplicates.
+	UniqueExact UniqueMode = iota
+	// UniqueBloom keeps a Bloom filter of seen values in a fixed amount of memory. It never misses a duplicate,
+	// but may report a value that was not seen before as a duplicate (a false positive).
+	UniqueBloom
+)
+
+// UniqueOptions configures NewUniqueReader.
+type UniqueOptions struct {
+	// Mode selects exact or approximate tracking of values.
+	Mode UniqueMode
+	// MaxValues limits the number of distinct values kept in memory in the exact mode. Reading more values fails
+	// with ErrUniqueLimit. Zero means no limit.
+	//
+	// In the Bloom mode it's the expected number of distinct values, which is used to size the filter
+	// (1<<20 by default). The false positive rate grows quickly if the input has more values.
+	MaxValues int
+	// FalsePositiveRate is the probability of a false duplicate in the Bloom mode after reading MaxValues
+	// distinct values (0.01 by default). The filter uses about 1.44*log2(1/rate) bits per value.
+	FalsePositiveRate float64
+}
+
+// ErrUniqueLimit is returned by a reader created with NewUniqueReader if it exceeds UniqueOptions.MaxValues.
+var ErrUniqueLimit = errors.New("pquads: too many distinct values to check uniqueness")
+
+// DuplicateError is returned by a reader created with NewUniqueReader when a value is repeated.
+type DuplicateError struct {
+	Ordinal   int // zero-based index of the offending quad in the stream
+	Direction quad.Direction
+	Value     quad.Value
+	// Approximate is set if the duplicate was detected by a Bloom filter and may be a false positive.
+	Approximate bool
+}
+
+func (e *DuplicateError) Error() string {
+	if e.Approximate {
+		return fmt.Sprintf("quad %d: duplicate %v (may be a false positive): %v", e.Ordinal, e.Direction, e.Value)
+	}
+	return fmt.Sprintf("quad %d: duplicate %v: %v", e.Ordinal, e.Direction, e.Value)
+}
+
+var _ quad.ReadCloser = (*UniqueReader)(nil)
+
+// UniqueReader is a quad reader that verifies that values in one direction are never repeated.
+type UniqueReader struct {
+	r     *Reader
+	dir   quad.Direction
+	max   int
+	seen  map[string]struct{} // exact mode
+	bloom *bloomFilter        // Bloom mode
+	n     int
+	err   error
+}
+
+// NewUniqueReader wraps a reader to check that each value in a given direction occurs in at most one quad,
+// for example that each subject is described by a single quad. Quads without a value in this direction
+// (like quads in the default graph for quad.Label) are not checked. Options can be nil to use the exact mode.
+//
+// At the first duplicate it returns a *DuplicateError and fails all subsequent reads with the same error.
+func NewUniqueReader(r *Reader, dir quad.Direction, opts *UniqueOptions) *UniqueReader {
+	if opts == nil {
+		opts = &UniqueOptions{}
+	}
+	u := &UniqueReader{r: r, dir: dir}
+	switch opts.Mode {
+	case UniqueBloom:
+		n, rate := opts.MaxValues, opts.FalsePositiveRate
+		if n <= 0 {
+			n = 1 << 20
+		}
+		if rate <= 0 || rate >= 1 {
+			rate = 0.01
+		}
+		u.bloom = newBloomFilter(n, rate)
+	default:
+		u.max = opts.MaxValues
+		u.seen = make(map[string]struct{})
+	}
+	return u
+}
+
+func (r *UniqueReader) ReadQuad(ctx context.Context) (quad.Quad, error) {
+	if r.err != nil {
+		return quad.Quad{}, r.err
+	}
+	q, err := r.r.ReadQuad(ctx)
+	if err != nil {
+		return q, err
+	}
+	i := r.n
+	r.n++
+	v := q.Get(r.dir)
+	if v == nil {
+		return q, nil
+	}
+	if r.bloom != nil {
+		if r.bloom.add(quad.HashOf(v)) {
+			r.err = &DuplicateError{Ordinal: i, Direction: r.dir, Value: v, Approximate: true}
+			return quad.Quad{}, r.err
+		}
+		return q, nil
+	}
+	key := quad.StringOf(v)
+	if _, ok := r.seen[key]; ok {
+		r.err = &DuplicateError{Ordinal: i, Direction: r.dir, Value: v}
+		return quad.Quad{}, r.err
+	} else if r.max > 0 && len(r.seen) >= r.max {
+		r.err = fmt.Errorf("quad %d: %w", i, ErrUniqueLimit)
+		return quad.Quad{}, r.err
+	}
+	r.seen[key] = struct{}{}
+	return q, nil
+}
+
+func (r *UniqueReader) Close() error {
+	return r.r.Close()
+}
+
+// bloomFilter is a Bloom filter that uses double hashing of value hashes.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    int    // number of hash functions
+}
+
+func newBloomFilter(n int, rate float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// add adds a hash to the filter and reports whether it was (probably) added before.
+func (f *bloomFilter) add(h []byte) bool {
+	h1 := binary.LittleEndian.Uint64(h[0:8])
+	h2 := binary.LittleEndian.Uint64(h[8:16]) | 1
+	seen := true
+	for i := 0; i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % f.m
+		w, mask := b/64, uint64(1)<<(b%64)
+		if f.bits[w]&mask == 0 {
+			seen = false
+			f.bits[w] |= mask
+		}
+	}
+	return seen
+}
+
+// contains reports whether a hash was (probably) added to the filter.
+func (f *bloomFilter) contains(h []byte) bool {
+	h1 := binary.LittleEndian.Uint64(h[0:8])
+	h2 := binary.LittleEndian.Uint64(h[8:16]) | 1
+	for i := 0; i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % f.m
+		if f.bits[b/64]&(uint64(1)<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestUniqueReader(t *testing.T) {
+	ctx := context.Background()
+	in := make([]quad.Quad, 1000)
+	for i := range in {
+		in[i] = quad.MakeIRI(fmt.Sprintf("s%d", i), "p", "o", "")
+	}
+	modes := []struct {
+		name string
+		opts *pquads.UniqueOptions
+	}{
+		{"exact", nil},
+		{"bloom", &pquads.UniqueOptions{Mode: pquads.UniqueBloom, MaxValues: len(in), FalsePositiveRate: 1e-6}},
+	}
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			data := writeQuads(t, nil, in)
+			out, err := quad.ReadAll(ctx, pquads.NewUniqueReader(pquads.NewReader(bytes.NewReader(data), 0), quad.Subject, m.opts))
+			if err != nil {
+				t.Fatal(err)
+			} else if len(out) != len(in) {
+				t.Fatalf("expected %d quads, got %d", len(in), len(out))
+			}
+			// labels are not set, thus not checked
+			if _, err = quad.ReadAll(ctx, pquads.NewUniqueReader(pquads.NewReader(bytes.NewReader(data), 0), quad.Label, m.opts)); err != nil {
+				t.Fatal(err)
+			}
+
+			dup := make([]quad.Quad, len(in))
+			copy(dup, in)
+			dup[700] = in[300]
+			r := pquads.NewUniqueReader(pquads.NewReader(bytes.NewReader(writeQuads(t, nil, dup)), 0), quad.Subject, m.opts)
+			_, err = quad.ReadAll(ctx, r)
+			var e *pquads.DuplicateError
+			if !errors.As(err, &e) {
+				t.Fatalf("expected duplicate error, got: %v", err)
+			} else if e.Ordinal != 700 || e.Value != in[300].Subject || e.Direction != quad.Subject {
+				t.Fatalf("unexpected error: %v", e)
+			} else if e.Approximate != (m.opts != nil) {
+				t.Fatalf("unexpected approximation flag: %v", e)
+			}
+			if _, err2 := r.ReadQuad(ctx); err2 != err {
+				t.Fatalf("expected the same error, got: %v", err2)
+			}
+		})
+	}
+	t.Run("limit", func(t *testing.T) {
+		r := pquads.NewUniqueReader(pquads.NewReader(bytes.NewReader(writeQuads(t, nil, in)), 0),
+			quad.Subject, &pquads.UniqueOptions{MaxValues: 100})
+		n := 0
+		for ; ; n++ {
+			if _, err := r.ReadQuad(ctx); err != nil {
+				if !errors.Is(err, pquads.ErrUniqueLimit) {
+					t.Fatalf("expected limit error, got: %v", err)
+				}
+				break
+			}
+		}
+		if n != 100 {
+			t.Fatalf("expected 100 quads, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package pquads
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/cayleygraph/quad"
+)
+
+// ErrInvalidUTF8 is returned when a quad value contains a string that is not a valid UTF-8.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8 string")
+
+// kinds of BinaryString values
+const (
+	binString = iota
+	binIRI
+	binBNode
+	binTyped
+	binLang
+)
+
+// validUTF8 checks if all strings of a value are valid UTF-8.
+func validUTF8(v quad.Value) bool {
+	switch v := v.(type) {
+	case quad.String:
+		return utf8.ValidString(string(v))
+	case quad.IRI:
+		return utf8.ValidString(string(v))
+	case quad.BNode:
+		return utf8.ValidString(string(v))
+	case quad.TypedString:
+		return utf8.ValidString(string(v.Value)) && utf8.ValidString(string(v.Type))
+	case quad.LangString:
+		return utf8.ValidString(string(v.Value)) && utf8.ValidString(v.Lang)
+	}
+	return true
+}
+
+// makeBinaryValue converts a string-based value to its binary representation that preserves exact bytes.
+func makeBinaryValue(v quad.Value) *Value {
+	var b *Value_BinaryString
+	switch v := v.(type) {
+	case quad.String:
+		b = &Value_BinaryString{Kind: binString, Value: []byte(v)}
+	case quad.IRI:
+		b = &Value_BinaryString{Kind: binIRI, Value: []byte(v)}
+	case quad.BNode:
+		b = &Value_BinaryString{Kind: binBNode, Value: []byte(v)}
+	case quad.TypedString:
+		b = &Value_BinaryString{Kind: binTyped, Value: []byte(v.Value), Type: []byte(v.Type)}
+	case quad.LangString:
+		b = &Value_BinaryString{Kind: binLang, Value: []byte(v.Value), Type: []byte(v.Lang)}
+	default:
+		return MakeValue(v)
+	}
+	return &Value{Value: &Value_BinStr{BinStr: b}}
+}
+
+// ToNative converts a binary string to quad.Value.
+func (m *Value_BinaryString) ToNative() quad.Value {
+	switch m.Kind {
+	case binIRI:
+		return quad.IRI(m.Value)
+	case binBNode:
+		return quad.BNode(m.Value)
+	case binTyped:
+		return quad.TypedString{Value: quad.String(m.Value), Type: quad.IRI(m.Type)}
+	case binLang:
+		return quad.LangString{Value: quad.String(m.Value), Lang: string(m.Type)}
+	}
+	return quad.String(m.Value)
+}
+
+// preserveUTF8 replaces values with invalid UTF-8 in the message with their binary representation.
+func preserveUTF8(m interface{}, q quad.Quad) {
+	fix := func(p **Value, v quad.Value) {
+		if *p == nil || v == nil || validUTF8(v) {
+			return
+		}
+		if _, ok := (*p).Value.(*Value_Ref); ok {
+			return
+		}
+		*p = makeBinaryValue(v)
+	}
+	switch m := m.(type) {
+	case *WireQuad:
+		fix(&m.Subject, q.Subject)
+		fix(&m.Predicate, q.Predicate)
+		fix(&m.Object, q.Object)
+		fix(&m.Label, q.Label)
+	case *StrictQuad:
+		fix(&m.Object, q.Object)
+	}
+}
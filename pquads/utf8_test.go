@@ -0,0 +1,60 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestInvalidUTF8(t *testing.T) {
+	ctx := context.Background()
+	const bad = "a\xffb\xc3"
+	in := []quad.Quad{
+		{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String(bad)},
+		{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.TypedString{Value: bad, Type: "t\xff"}},
+		{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.LangString{Value: bad, Lang: "en"}},
+		{Subject: quad.IRI("s"), Predicate: quad.IRI("p"), Object: quad.String(bad)},
+	}
+	wire := []quad.Quad{
+		{Subject: quad.IRI(bad), Predicate: quad.IRI("p"), Object: quad.String("o")},
+		{Subject: quad.BNode(bad), Predicate: quad.IRI("p"), Object: quad.IRI(bad), Label: quad.IRI(bad)},
+	}
+	for _, opts := range []pquads.Options{
+		{},
+		{Strict: true},
+		{Full: true},
+		{Dictionary: true},
+	} {
+		w := pquads.NewWriter(bytes.NewBuffer(nil), &opts)
+		if err := w.WriteQuad(ctx, in[0]); !errors.Is(err, pquads.ErrInvalidUTF8) {
+			t.Fatalf("%+v: expected UTF-8 error, got: %v", opts, err)
+		}
+		// the quad is rejected without breaking the writer
+		if err := w.WriteQuad(ctx, quad.MakeIRI("s", "p", "o", "")); err != nil {
+			t.Fatal(err)
+		}
+
+		opts.PreserveInvalidUTF8 = true
+		exp := in
+		if !opts.Strict {
+			exp = append(append([]quad.Quad{}, in...), wire...)
+		}
+		out, err := quad.ReadAll(ctx, pquads.NewReader(bytes.NewReader(writeQuads(t, &opts, exp)), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(exp, out) {
+			t.Fatalf("%+v: unexpected quads:\n%q\n%q", opts, exp, out)
+		}
+		if opts.Strict {
+			w = pquads.NewWriter(bytes.NewBuffer(nil), &opts)
+			if err := w.WriteQuad(ctx, wire[0]); !errors.Is(err, pquads.ErrInvalidUTF8) {
+				t.Fatalf("expected UTF-8 error for strict ref, got: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package pquads_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/pquads"
+)
+
+func TestQuadVersions(t *testing.T) {
+	ctx := context.Background()
+	quads := makeQuads(30)
+	version := func(i int) quad.Value {
+		if i%4 == 0 {
+			return nil
+		}
+		return quad.Int(i % 3)
+	}
+	for _, opts := range []*pquads.Options{
+		nil,
+		{Full: true},
+		{Strict: true},
+		{Dictionary: true},
+		{Strict: true, Dictionary: true},
+	} {
+		buf := bytes.NewBuffer(nil)
+		w := pquads.NewWriter(buf, opts)
+		for i, q := range quads {
+			if err := w.WriteQuadVersioned(ctx, q, version(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r := pquads.NewReader(buf, 0)
+		for i := 0; ; i++ {
+			q, v, err := r.ReadQuadVersioned(ctx)
+			if err == io.EOF {
+				if i != len(quads) {
+					t.Fatalf("%+v: expected %d quads, got %d", opts, len(quads), i)
+				}
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if q != quads[i] {
+				t.Fatalf("%+v: quad %d: %v vs %v", opts, i, q, quads[i])
+			} else if v != version(i) {
+				t.Fatalf("%+v: version of quad %d: %v vs %v", opts, i, v, version(i))
+			}
+		}
+	}
+
+	// versions without values cost nothing
+	plain := writeQuads(t, nil, quads)
+	buf := bytes.NewBuffer(nil)
+	w := pquads.NewWriter(buf, nil)
+	for _, q := range quads {
+		if err := w.WriteQuadVersioned(ctx, q, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(buf.Bytes(), plain) {
+		t.Fatal("nil versions changed the encoding")
+	}
+}
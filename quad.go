@@ -40,6 +40,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 )
 
 var (
@@ -271,6 +272,23 @@ func (q Quad) NQuad() string {
 	return fmt.Sprintf("%s %s %s %s .", q.Subject, q.Predicate, q.Object, q.Label)
 }
 
+// HashQuad calculates a hash of quad q.
+//
+// It's a SHA-1 hash of concatenated hashes of all four directions (see HashOf), in the order of subject,
+// predicate, object and label; a missing label is hashed as a nil value. The hash is stable as long as
+// the string representation of values doesn't change.
+func HashQuad(q Quad) []byte {
+	var buf [4 * HashSize]byte
+	for i, d := range Directions {
+		HashTo(q.Get(d), buf[i*HashSize:])
+	}
+	h := hashPool.Get().(hash.Hash)
+	h.Reset()
+	defer hashPool.Put(h)
+	h.Write(buf[:])
+	return h.Sum(make([]byte, 0, HashSize))
+}
+
 type ByQuadString []Quad
 
 func (o ByQuadString) Len() int { return len(o) }
@@ -25,3 +25,17 @@ func TestHashOf(t *testing.T) {
 		}
 	}
 }
+
+func TestHashQuad(t *testing.T) {
+	for _, c := range []struct {
+		q    Quad
+		hash string
+	}{
+		{MakeIRI("a", "b", "c", ""), "ed9beb8060c11cdd9cecfa45fbd3551fdbe845bd"},
+		{MakeIRI("a", "b", "c", "d"), "28dc7b8d100c58f408d23e7677e06915381e0700"},
+	} {
+		if h := hex.EncodeToString(HashQuad(c.q)); h != c.hash {
+			t.Errorf("unexpected hash for %v: %v vs %v", c.q, h, c.hash)
+		}
+	}
+}
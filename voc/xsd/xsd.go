@@ -34,4 +34,32 @@ const (
 	Int = Prefix + `int`
 	// Float datatype is patterned after the IEEE single-precision 32-bit floating point datatype
 	Float = Prefix + `float`
+	// Decimal represents a subset of the real numbers, which can be represented by decimal numerals.
+	Decimal = Prefix + `decimal`
+	// NonPositiveInteger is derived from integer by setting the value of maxInclusive to be 0.
+	NonPositiveInteger = Prefix + `nonPositiveInteger`
+	// NegativeInteger is derived from nonPositiveInteger by setting the value of maxInclusive to be -1.
+	NegativeInteger = Prefix + `negativeInteger`
+	// NonNegativeInteger is derived from integer by setting the value of minInclusive to be 0.
+	NonNegativeInteger = Prefix + `nonNegativeInteger`
+	// PositiveInteger is derived from nonNegativeInteger by setting the value of minInclusive to be 1.
+	PositiveInteger = Prefix + `positiveInteger`
+	// Short is derived from int by setting the value of maxInclusive to be 32767 and minInclusive to be -32768.
+	Short = Prefix + `short`
+	// Byte is derived from short by setting the value of maxInclusive to be 127 and minInclusive to be -128.
+	Byte = Prefix + `byte`
+	// UnsignedLong is derived from nonNegativeInteger by setting the value of maxInclusive to be 18446744073709551615.
+	UnsignedLong = Prefix + `unsignedLong`
+	// UnsignedInt is derived from unsignedLong by setting the value of maxInclusive to be 4294967295.
+	UnsignedInt = Prefix + `unsignedInt`
+	// UnsignedShort is derived from unsignedInt by setting the value of maxInclusive to be 65535.
+	UnsignedShort = Prefix + `unsignedShort`
+	// UnsignedByte is derived from unsignedShort by setting the value of maxInclusive to be 255.
+	UnsignedByte = Prefix + `unsignedByte`
+)
+
+// Date and time types
+const (
+	// Date represents top-open intervals of exactly one day in length on the timelines of dateTime, beginning on the beginning moment of each day, up to but not including the beginning moment of the next day.
+	Date = Prefix + `date`
 )